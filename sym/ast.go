@@ -6,20 +6,44 @@ type Visitor interface {
 	visitAssignExpr(expr *AssignExpr) interface{}
 	visitBinaryExpr(expr *BinaryExpr) interface{}
 	visitCallExpr(expr *CallExpr) interface{}
+	visitCompoundAssignExpr(expr *CompoundAssignExpr) interface{}
+	visitConditionalExpr(expr *ConditionalExpr) interface{}
+	visitFunctionExpr(expr *FunctionExpr) interface{}
+	visitGetExpr(expr *GetExpr) interface{}
+	visitIndexExpr(expr *IndexExpr) interface{}
+	visitIndexSetExpr(expr *IndexSetExpr) interface{}
+	visitInterpolationExpr(expr *InterpolationExpr) interface{}
+	visitListExpr(expr *ListExpr) interface{}
 	visitLiteralExpr(expr *LiteralExpr) interface{}
 	visitLogicalExpr(expr *LogicalExpr) interface{}
+	visitMapExpr(expr *MapExpr) interface{}
+	visitMatchExpr(expr *MatchExpr) interface{}
+	visitSetExpr(expr *SetExpr) interface{}
+	visitSpawnExpr(expr *SpawnExpr) interface{}
+	visitSuperExpr(expr *SuperExpr) interface{}
+	visitThisExpr(expr *ThisExpr) interface{}
 	visitUnaryExpr(expr *UnaryExpr) interface{}
 	visitVarExpr(expr *VarExpr) interface{}
 
 	visitBlockStmt(stmt *BlockStmt) interface{}
 	visitBreakStmt(stmt *BreakStmt) interface{}
+	visitClassStmt(stmt *ClassStmt) interface{}
+	visitContinueStmt(stmt *ContinueStmt) interface{}
+	visitEnumStmt(stmt *EnumStmt) interface{}
+	visitExportStmt(stmt *ExportStmt) interface{}
 	visitExpressionStmt(stmt *ExpressionStmt) interface{}
 	visitFunctionStmt(stmt *FunctionStmt) interface{}
 	visitIfStmt(stmt *IfStmt) interface{}
+	visitImportStmt(stmt *ImportStmt) interface{}
 	visitLoopStmt(stmt *LoopStmt) interface{}
+	visitMatchStmt(stmt *MatchStmt) interface{}
 	visitPrintStmt(stmt *PrintStmt) interface{}
+	visitRecordStmt(stmt *RecordStmt) interface{}
 	visitReturnStmt(stmt *ReturnStmt) interface{}
+	visitThrowStmt(stmt *ThrowStmt) interface{}
+	visitTryStmt(stmt *TryStmt) interface{}
 	visitVarStmt(stmt *VarStmt) interface{}
+	visitYieldStmt(stmt *YieldStmt) interface{}
 }
 
 /*
@@ -72,17 +96,22 @@ func (be *BinaryExpr) String() string {
 		be.Left, be.Operator, be.Right)
 }
 
+// CallExpr's Names and NamedArguments are parallel.
 type CallExpr struct {
-	Callee      Expr
-	Parenthesis Token
-	Arguments   []Expr
+	Callee         Expr
+	Parenthesis    Token
+	Arguments      []Expr
+	Names          []Token
+	NamedArguments []Expr
 }
 
-func NewCallExpr(callee Expr, parenthesis Token, arguments []Expr) *CallExpr {
+func NewCallExpr(callee Expr, parenthesis Token, arguments []Expr, names []Token, namedArguments []Expr) *CallExpr {
 	return &CallExpr{
-		Callee:      callee,
-		Parenthesis: parenthesis,
-		Arguments:   arguments,
+		Callee:         callee,
+		Parenthesis:    parenthesis,
+		Arguments:      arguments,
+		Names:          names,
+		NamedArguments: namedArguments,
 	}
 }
 
@@ -91,8 +120,180 @@ func (ce *CallExpr) Accept(visitor Visitor) interface{} {
 }
 
 func (ce *CallExpr) String() string {
-	return fmt.Sprintf("CallExpr {Callee:%v,Parenthesis: %v,Arguments: %v}",
-		ce.Callee, ce.Parenthesis, ce.Arguments)
+	return fmt.Sprintf("CallExpr {Callee:%v,Parenthesis: %v,Arguments: %v,Names: %v,NamedArguments: %v}",
+		ce.Callee, ce.Parenthesis, ce.Arguments, ce.Names, ce.NamedArguments)
+}
+
+// CompoundAssignExpr evaluates to the old value of its target when Postfix is set.
+type CompoundAssignExpr struct {
+	Target   Expr
+	Operator Token
+	Value    Expr
+	Postfix  bool
+}
+
+func NewCompoundAssignExpr(target Expr, operator Token, value Expr, postfix bool) *CompoundAssignExpr {
+	return &CompoundAssignExpr{
+		Target:   target,
+		Operator: operator,
+		Value:    value,
+		Postfix:  postfix,
+	}
+}
+
+func (cae *CompoundAssignExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitCompoundAssignExpr(cae)
+}
+
+func (cae *CompoundAssignExpr) String() string {
+	return fmt.Sprintf("CompoundAssignExpr {Target: %v,Operator: %v,Value: %v,Postfix: %v}", cae.Target, cae.Operator, cae.Value, cae.Postfix)
+}
+
+type ConditionalExpr struct {
+	Condition Expr
+	Then      Expr
+	Else      Expr
+}
+
+func NewConditionalExpr(condition Expr, then Expr, elseBranch Expr) *ConditionalExpr {
+	return &ConditionalExpr{
+		Condition: condition,
+		Then:      then,
+		Else:      elseBranch,
+	}
+}
+
+func (ce *ConditionalExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitConditionalExpr(ce)
+}
+
+func (ce *ConditionalExpr) String() string {
+	return fmt.Sprintf("ConditionalExpr {Condition: %v,Then: %v,Else: %v}", ce.Condition, ce.Then, ce.Else)
+}
+
+type FunctionExpr struct {
+	Declaration *FunctionStmt
+}
+
+func NewFunctionExpr(declaration *FunctionStmt) *FunctionExpr {
+	return &FunctionExpr{
+		Declaration: declaration,
+	}
+}
+
+func (fe *FunctionExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitFunctionExpr(fe)
+}
+
+func (fe *FunctionExpr) String() string {
+	return fmt.Sprintf("FunctionExpr {Declaration: %v}", fe.Declaration)
+}
+
+type GetExpr struct {
+	Object Expr
+	Name   Token
+}
+
+func NewGetExpr(object Expr, name Token) *GetExpr {
+	return &GetExpr{
+		Object: object,
+		Name:   name,
+	}
+}
+
+func (ge *GetExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitGetExpr(ge)
+}
+
+func (ge *GetExpr) String() string {
+	return fmt.Sprintf("GetExpr {Object: %v,Name: %v}", ge.Object, ge.Name)
+}
+
+type IndexExpr struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+}
+
+func NewIndexExpr(object Expr, bracket Token, index Expr) *IndexExpr {
+	return &IndexExpr{
+		Object:  object,
+		Bracket: bracket,
+		Index:   index,
+	}
+}
+
+func (ie *IndexExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitIndexExpr(ie)
+}
+
+func (ie *IndexExpr) String() string {
+	return fmt.Sprintf("IndexExpr {Object: %v,Bracket: %v,Index: %v}", ie.Object, ie.Bracket, ie.Index)
+}
+
+type IndexSetExpr struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+	Value   Expr
+}
+
+func NewIndexSetExpr(object Expr, bracket Token, index Expr, value Expr) *IndexSetExpr {
+	return &IndexSetExpr{
+		Object:  object,
+		Bracket: bracket,
+		Index:   index,
+		Value:   value,
+	}
+}
+
+func (ise *IndexSetExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitIndexSetExpr(ise)
+}
+
+func (ise *IndexSetExpr) String() string {
+	return fmt.Sprintf("IndexSetExpr {Object: %v,Bracket: %v,Index: %v,Value: %v}",
+		ise.Object, ise.Bracket, ise.Index, ise.Value)
+}
+
+type InterpolationExpr struct {
+	Token Token
+	Parts []Expr
+}
+
+func NewInterpolationExpr(token Token, parts []Expr) *InterpolationExpr {
+	return &InterpolationExpr{
+		Token: token,
+		Parts: parts,
+	}
+}
+
+func (ie *InterpolationExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitInterpolationExpr(ie)
+}
+
+func (ie *InterpolationExpr) String() string {
+	return fmt.Sprintf("InterpolationExpr {Token: %v,Parts: %v}", ie.Token, ie.Parts)
+}
+
+type ListExpr struct {
+	Bracket  Token
+	Elements []Expr
+}
+
+func NewListExpr(bracket Token, elements []Expr) *ListExpr {
+	return &ListExpr{
+		Bracket:  bracket,
+		Elements: elements,
+	}
+}
+
+func (le *ListExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitListExpr(le)
+}
+
+func (le *ListExpr) String() string {
+	return fmt.Sprintf("ListExpr {Bracket: %v,Elements: %v}", le.Bracket, le.Elements)
 }
 
 type LiteralExpr struct {
@@ -136,6 +337,130 @@ func (le *LogicalExpr) String() string {
 		le.Left, le.Operator, le.Right)
 }
 
+type MapExpr struct {
+	Bracket Token
+	Keys    []Expr
+	Values  []Expr
+}
+
+func NewMapExpr(bracket Token, keys []Expr, values []Expr) *MapExpr {
+	return &MapExpr{
+		Bracket: bracket,
+		Keys:    keys,
+		Values:  values,
+	}
+}
+
+func (me *MapExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitMapExpr(me)
+}
+
+func (me *MapExpr) String() string {
+	return fmt.Sprintf("MapExpr {Bracket: %v,Keys: %v,Values: %v}", me.Bracket, me.Keys, me.Values)
+}
+
+type MatchExpr struct {
+	Keyword Token
+	Subject Expr
+	Arms    []*MatchArm
+}
+
+func NewMatchExpr(keyword Token, subject Expr, arms []*MatchArm) *MatchExpr {
+	return &MatchExpr{
+		Keyword: keyword,
+		Subject: subject,
+		Arms:    arms,
+	}
+}
+
+func (me *MatchExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitMatchExpr(me)
+}
+
+func (me *MatchExpr) String() string {
+	return fmt.Sprintf("MatchExpr {Keyword: %v,Subject: %v,Arms: %v}", me.Keyword, me.Subject, me.Arms)
+}
+
+type SetExpr struct {
+	Object Expr
+	Name   Token
+	Value  Expr
+}
+
+func NewSetExpr(object Expr, name Token, value Expr) *SetExpr {
+	return &SetExpr{
+		Object: object,
+		Name:   name,
+		Value:  value,
+	}
+}
+
+func (se *SetExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitSetExpr(se)
+}
+
+func (se *SetExpr) String() string {
+	return fmt.Sprintf("SetExpr {Object: %v,Name: %v,Value: %v}", se.Object, se.Name, se.Value)
+}
+
+type SpawnExpr struct {
+	Keyword Token
+	Call    *CallExpr
+}
+
+func NewSpawnExpr(keyword Token, call *CallExpr) *SpawnExpr {
+	return &SpawnExpr{
+		Keyword: keyword,
+		Call:    call,
+	}
+}
+
+func (se *SpawnExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitSpawnExpr(se)
+}
+
+func (se *SpawnExpr) String() string {
+	return fmt.Sprintf("SpawnExpr {Keyword: %v,Call: %v}", se.Keyword, se.Call)
+}
+
+type SuperExpr struct {
+	Keyword Token
+	Method  Token
+}
+
+func NewSuperExpr(keyword Token, method Token) *SuperExpr {
+	return &SuperExpr{
+		Keyword: keyword,
+		Method:  method,
+	}
+}
+
+func (se *SuperExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitSuperExpr(se)
+}
+
+func (se *SuperExpr) String() string {
+	return fmt.Sprintf("SuperExpr {Keyword: %v,Method: %v}", se.Keyword, se.Method)
+}
+
+type ThisExpr struct {
+	Keyword Token
+}
+
+func NewThisExpr(keyword Token) *ThisExpr {
+	return &ThisExpr{
+		Keyword: keyword,
+	}
+}
+
+func (te *ThisExpr) Accept(visitor Visitor) interface{} {
+	return visitor.visitThisExpr(te)
+}
+
+func (te *ThisExpr) String() string {
+	return fmt.Sprintf("ThisExpr {Keyword: %v}", te.Keyword)
+}
+
 type UnaryExpr struct {
 	Operator Token
 	Right    Expr
@@ -201,10 +526,14 @@ func (bs *BlockStmt) String() string {
 
 type BreakStmt struct {
 	Token Token
+	Label *Token
 }
 
-func NewBreakStmt(token Token) *BreakStmt {
-	return &BreakStmt{}
+func NewBreakStmt(token Token, label *Token) *BreakStmt {
+	return &BreakStmt{
+		Token: token,
+		Label: label,
+	}
 }
 
 func (bs *BreakStmt) Accept(visitor Visitor) interface{} {
@@ -212,7 +541,112 @@ func (bs *BreakStmt) Accept(visitor Visitor) interface{} {
 }
 
 func (bs *BreakStmt) String() string {
-	return fmt.Sprintf("BreakStmt {Token: %v}", bs.Token)
+	return fmt.Sprintf("BreakStmt {Token: %v,Label: %v}", bs.Token, bs.Label)
+}
+
+type ClassStmt struct {
+	Name       Token
+	Superclass *VarExpr
+	Fields     []*VarStmt
+	Methods    []*FunctionStmt
+}
+
+func NewClassStmt(name Token, superclass *VarExpr, fields []*VarStmt, methods []*FunctionStmt) *ClassStmt {
+	return &ClassStmt{
+		Name:       name,
+		Superclass: superclass,
+		Fields:     fields,
+		Methods:    methods,
+	}
+}
+
+func (cs *ClassStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitClassStmt(cs)
+}
+
+func (cs *ClassStmt) String() string {
+	return fmt.Sprintf("ClassStmt {Name: %v,Superclass: %v,Fields: %v,Methods: %v}",
+		cs.Name, cs.Superclass, cs.Fields, cs.Methods)
+}
+
+type ContinueStmt struct {
+	Token Token
+	Label *Token
+}
+
+func NewContinueStmt(token Token, label *Token) *ContinueStmt {
+	return &ContinueStmt{
+		Token: token,
+		Label: label,
+	}
+}
+
+func (cs *ContinueStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitContinueStmt(cs)
+}
+
+func (cs *ContinueStmt) String() string {
+	return fmt.Sprintf("ContinueStmt {Token: %v,Label: %v}", cs.Token, cs.Label)
+}
+
+type EnumStmt struct {
+	Name     Token
+	Variants []*EnumVariant
+}
+
+func NewEnumStmt(name Token, variants []*EnumVariant) *EnumStmt {
+	return &EnumStmt{
+		Name:     name,
+		Variants: variants,
+	}
+}
+
+func (es *EnumStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitEnumStmt(es)
+}
+
+func (es *EnumStmt) String() string {
+	return fmt.Sprintf("EnumStmt {Name: %v,Variants: %v}", es.Name, es.Variants)
+}
+
+// EnumVariant has nil Fields when it is declared without parentheses.
+type EnumVariant struct {
+	Name   Token
+	Fields []Token
+}
+
+func NewEnumVariant(name Token, fields []Token) *EnumVariant {
+	return &EnumVariant{
+		Name:   name,
+		Fields: fields,
+	}
+}
+
+func (ev *EnumVariant) String() string {
+	return fmt.Sprintf("EnumVariant {Name: %v,Fields: %v}", ev.Name, ev.Fields)
+}
+
+// ExportStmt's Name is the name that its declaration binds.
+type ExportStmt struct {
+	Keyword     Token
+	Name        Token
+	Declaration Stmt
+}
+
+func NewExportStmt(keyword Token, name Token, declaration Stmt) *ExportStmt {
+	return &ExportStmt{
+		Keyword:     keyword,
+		Name:        name,
+		Declaration: declaration,
+	}
+}
+
+func (es *ExportStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitExportStmt(es)
+}
+
+func (es *ExportStmt) String() string {
+	return fmt.Sprintf("ExportStmt {Keyword: %v,Name: %v,Declaration: %v}", es.Keyword, es.Name, es.Declaration)
 }
 
 type ExpressionStmt struct {
@@ -233,17 +667,22 @@ func (es *ExpressionStmt) String() string {
 	return fmt.Sprintf("ExpressionStmt {Expression: %v}", es.Expression)
 }
 
+// FunctionStmt's Defaults is nil for parameters without a default value.
 type FunctionStmt struct {
-	Name   Token
-	Params []Token
-	Body   []Stmt
+	Name     Token
+	Params   []Token
+	Defaults []Expr
+	Rest     *Token
+	Body     []Stmt
 }
 
-func NewFunctionStmt(name Token, params []Token, body []Stmt) *FunctionStmt {
+func NewFunctionStmt(name Token, params []Token, defaults []Expr, rest *Token, body []Stmt) *FunctionStmt {
 	return &FunctionStmt{
-		Name:   name,
-		Params: params,
-		Body:   body,
+		Name:     name,
+		Params:   params,
+		Defaults: defaults,
+		Rest:     rest,
+		Body:     body,
 	}
 }
 
@@ -252,18 +691,21 @@ func (fs *FunctionStmt) Accept(visitor Visitor) interface{} {
 }
 
 func (fs *FunctionStmt) String() string {
-	return fmt.Sprintf("FunctionStmt {Name: %v,Params: %v,Body: %v}", fs.Name, fs.Params, fs.Body)
+	return fmt.Sprintf("FunctionStmt {Name: %v,Params: %v,Defaults: %v,Rest: %v,Body: %v}",
+		fs.Name, fs.Params, fs.Defaults, fs.Rest, fs.Body)
 }
 
 type IfStmt struct {
 	Condition Expr
 	Then      Stmt
+	Else      Stmt
 }
 
-func NewIfStmt(condition Expr, then Stmt) *IfStmt {
+func NewIfStmt(condition Expr, then Stmt, elseBranch Stmt) *IfStmt {
 	return &IfStmt{
 		Condition: condition,
 		Then:      then,
+		Else:      elseBranch,
 	}
 }
 
@@ -272,16 +714,48 @@ func (is *IfStmt) Accept(visitor Visitor) interface{} {
 }
 
 func (is *IfStmt) String() string {
-	return fmt.Sprintf("IfStmt {Condition: %v,Then: %v}", is.Condition, is.Then)
+	return fmt.Sprintf("IfStmt {Condition: %v,Then: %v,Else: %v}", is.Condition, is.Then, is.Else)
+}
+
+type ImportStmt struct {
+	Keyword Token
+	Path    Token
+	Alias   Token
+}
+
+func NewImportStmt(keyword Token, path Token, alias Token) *ImportStmt {
+	return &ImportStmt{
+		Keyword: keyword,
+		Path:    path,
+		Alias:   alias,
+	}
+}
+
+func (is *ImportStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitImportStmt(is)
+}
+
+func (is *ImportStmt) String() string {
+	return fmt.Sprintf("ImportStmt {Keyword: %v,Path: %v,Alias: %v}", is.Keyword, is.Path, is.Alias)
 }
 
 type LoopStmt struct {
-	Body Stmt
+	Label       *Token
+	Initializer Stmt
+	Condition   Expr
+	Increment   Expr
+	Iterable    Expr
+	Body        Stmt
 }
 
-func NewLoopStmt(body Stmt) *LoopStmt {
+func NewLoopStmt(label *Token, initializer Stmt, condition Expr, increment Expr, iterable Expr, body Stmt) *LoopStmt {
 	return &LoopStmt{
-		Body: body,
+		Label:       label,
+		Initializer: initializer,
+		Condition:   condition,
+		Increment:   increment,
+		Iterable:    iterable,
+		Body:        body,
 	}
 }
 
@@ -290,7 +764,30 @@ func (ls *LoopStmt) Accept(visitor Visitor) interface{} {
 }
 
 func (ls *LoopStmt) String() string {
-	return fmt.Sprintf("LoopStmt {Body: %v}", ls.Body)
+	return fmt.Sprintf("LoopStmt {Label: %v,Initializer: %v,Condition: %v,Increment: %v,Iterable: %v,Body: %v}",
+		ls.Label, ls.Initializer, ls.Condition, ls.Increment, ls.Iterable, ls.Body)
+}
+
+type MatchStmt struct {
+	Keyword Token
+	Subject Expr
+	Arms    []*MatchArm
+}
+
+func NewMatchStmt(keyword Token, subject Expr, arms []*MatchArm) *MatchStmt {
+	return &MatchStmt{
+		Keyword: keyword,
+		Subject: subject,
+		Arms:    arms,
+	}
+}
+
+func (ms *MatchStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitMatchStmt(ms)
+}
+
+func (ms *MatchStmt) String() string {
+	return fmt.Sprintf("MatchStmt {Keyword: %v,Subject: %v,Arms: %v}", ms.Keyword, ms.Subject, ms.Arms)
 }
 
 type PrintStmt struct {
@@ -311,6 +808,26 @@ func (ps *PrintStmt) String() string {
 	return fmt.Sprintf("PrintStmt {Expression: %v}", ps.Expression)
 }
 
+type RecordStmt struct {
+	Name   Token
+	Fields []Token
+}
+
+func NewRecordStmt(name Token, fields []Token) *RecordStmt {
+	return &RecordStmt{
+		Name:   name,
+		Fields: fields,
+	}
+}
+
+func (rs *RecordStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitRecordStmt(rs)
+}
+
+func (rs *RecordStmt) String() string {
+	return fmt.Sprintf("RecordStmt {Name: %v,Fields: %v}", rs.Name, rs.Fields)
+}
+
 type ReturnStmt struct {
 	Keyword Token
 	Value   Expr
@@ -331,15 +848,64 @@ func (rs *ReturnStmt) String() string {
 	return fmt.Sprintf("ReturnStmt {Keyword: %v,Value: %v}", rs.Keyword, rs.Value)
 }
 
+type ThrowStmt struct {
+	Keyword Token
+	Value   Expr
+}
+
+func NewThrowStmt(keyword Token, value Expr) *ThrowStmt {
+	return &ThrowStmt{
+		Keyword: keyword,
+		Value:   value,
+	}
+}
+
+func (ts *ThrowStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitThrowStmt(ts)
+}
+
+func (ts *ThrowStmt) String() string {
+	return fmt.Sprintf("ThrowStmt {Keyword: %v,Value: %v}", ts.Keyword, ts.Value)
+}
+
+type TryStmt struct {
+	Keyword   Token
+	Body      *BlockStmt
+	CatchName *Token
+	Catch     *BlockStmt
+	Finally   *BlockStmt
+}
+
+func NewTryStmt(keyword Token, body *BlockStmt, catchName *Token, catch *BlockStmt, finally *BlockStmt) *TryStmt {
+	return &TryStmt{
+		Keyword:   keyword,
+		Body:      body,
+		CatchName: catchName,
+		Catch:     catch,
+		Finally:   finally,
+	}
+}
+
+func (ts *TryStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitTryStmt(ts)
+}
+
+func (ts *TryStmt) String() string {
+	return fmt.Sprintf("TryStmt {Keyword: %v,Body: %v,CatchName: %v,Catch: %v,Finally: %v}",
+		ts.Keyword, ts.Body, ts.CatchName, ts.Catch, ts.Finally)
+}
+
 type VarStmt struct {
 	Name        Token
 	Initializer Expr
+	Constant    bool
 }
 
-func NewVarStmt(name Token, initializer Expr) *VarStmt {
+func NewVarStmt(name Token, initializer Expr, constant bool) *VarStmt {
 	return &VarStmt{
 		Name:        name,
 		Initializer: initializer,
+		Constant:    constant,
 	}
 }
 
@@ -348,5 +914,152 @@ func (vs *VarStmt) Accept(visitor Visitor) interface{} {
 }
 
 func (vs *VarStmt) String() string {
-	return fmt.Sprintf("VarStmt {Name: %v,Initializer: %v}", vs.Name, vs.Initializer)
+	return fmt.Sprintf("VarStmt {Name: %v,Initializer: %v,Constant: %v}", vs.Name, vs.Initializer, vs.Constant)
+}
+
+type YieldStmt struct {
+	Keyword Token
+	Value   Expr
+}
+
+func NewYieldStmt(keyword Token, value Expr) *YieldStmt {
+	return &YieldStmt{
+		Keyword: keyword,
+		Value:   value,
+	}
+}
+
+func (ys *YieldStmt) Accept(visitor Visitor) interface{} {
+	return visitor.visitYieldStmt(ys)
+}
+
+func (ys *YieldStmt) String() string {
+	return fmt.Sprintf("YieldStmt {Keyword: %v,Value: %v}", ys.Keyword, ys.Value)
+}
+
+/*
+/ Patterns
+*/
+
+// Patterns are matched with type switches rather than a visitor.
+type Pattern interface {
+	String() string
+}
+
+// MatchArm has a Body in a match statement and a Value in a match expression.
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expr
+	Body    Stmt
+	Value   Expr
+}
+
+func NewMatchArm(pattern Pattern, guard Expr, body Stmt, value Expr) *MatchArm {
+	return &MatchArm{
+		Pattern: pattern,
+		Guard:   guard,
+		Body:    body,
+		Value:   value,
+	}
+}
+
+func (ma *MatchArm) String() string {
+	return fmt.Sprintf("MatchArm {Pattern: %v,Guard: %v,Body: %v,Value: %v}", ma.Pattern, ma.Guard, ma.Body, ma.Value)
+}
+
+type BindingPattern struct {
+	Name Token
+}
+
+func NewBindingPattern(name Token) *BindingPattern {
+	return &BindingPattern{
+		Name: name,
+	}
+}
+
+func (bp *BindingPattern) String() string {
+	return fmt.Sprintf("BindingPattern {Name: %v}", bp.Name)
+}
+
+type ConstructorPattern struct {
+	Constructor Expr
+	Parenthesis Token
+	Fields      []Pattern
+}
+
+func NewConstructorPattern(constructor Expr, parenthesis Token, fields []Pattern) *ConstructorPattern {
+	return &ConstructorPattern{
+		Constructor: constructor,
+		Parenthesis: parenthesis,
+		Fields:      fields,
+	}
+}
+
+func (cp *ConstructorPattern) String() string {
+	return fmt.Sprintf("ConstructorPattern {Constructor: %v,Parenthesis: %v,Fields: %v}", cp.Constructor, cp.Parenthesis, cp.Fields)
+}
+
+type ListPattern struct {
+	Bracket  Token
+	Elements []Pattern
+	Rest     *Token
+}
+
+func NewListPattern(bracket Token, elements []Pattern, rest *Token) *ListPattern {
+	return &ListPattern{
+		Bracket:  bracket,
+		Elements: elements,
+		Rest:     rest,
+	}
+}
+
+func (lp *ListPattern) String() string {
+	return fmt.Sprintf("ListPattern {Bracket: %v,Elements: %v,Rest: %v}", lp.Bracket, lp.Elements, lp.Rest)
+}
+
+// MapPattern ignores other keys, but with no keys only matches an empty map.
+type MapPattern struct {
+	Bracket Token
+	Keys    []Expr
+	Values  []Pattern
+}
+
+func NewMapPattern(bracket Token, keys []Expr, values []Pattern) *MapPattern {
+	return &MapPattern{
+		Bracket: bracket,
+		Keys:    keys,
+		Values:  values,
+	}
+}
+
+func (mp *MapPattern) String() string {
+	return fmt.Sprintf("MapPattern {Bracket: %v,Keys: %v,Values: %v}", mp.Bracket, mp.Keys, mp.Values)
+}
+
+type ValuePattern struct {
+	Value Expr
+}
+
+func NewValuePattern(value Expr) *ValuePattern {
+	return &ValuePattern{
+		Value: value,
+	}
+}
+
+func (vp *ValuePattern) String() string {
+	return fmt.Sprintf("ValuePattern {Value: %v}", vp.Value)
+}
+
+type WildcardPattern struct {
+	Token Token
+}
+
+func NewWildcardPattern(token Token) *WildcardPattern {
+	return &WildcardPattern{
+		Token: token,
+	}
+}
+
+func (wp *WildcardPattern) String() string {
+	return fmt.Sprintf("WildcardPattern {Token: %v}", wp.Token)
 }
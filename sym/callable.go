@@ -1,27 +1,121 @@
 package sym
 
+import (
+	"fmt"
+	"strings"
+)
+
 type SymCallable interface {
-	Arity() int
+	Arity() Arity
 	Call(interpreter *Interpreter, arguments []interface{}) interface{}
+	Parameters() []string
+	Signature() string
+}
+
+// missingArgument stands in for an optional argument skipped by name.
+type missingArgument struct{}
+
+var missing interface{} = missingArgument{}
+
+// Max is -1 when a callable takes any number of extra arguments.
+type Arity struct {
+	Min int
+	Max int
+}
+
+func NewArity(min int, max int) Arity {
+	return Arity{
+		Min: min,
+		Max: max,
+	}
+}
+
+func (a Arity) accepts(count int) bool {
+	return count >= a.Min && (a.Max == -1 || count <= a.Max)
+}
+
+func (a Arity) String() string {
+	noun := "arguments"
+	if (a.Max == -1 && a.Min == 1) || (a.Max == 1 && a.Min == 1) {
+		noun = "argument"
+	}
+	switch {
+	case a.Max == -1:
+		return fmt.Sprintf("at least %d %s", a.Min, noun)
+	case a.Min == a.Max:
+		return fmt.Sprintf("%d %s", a.Min, noun)
+	default:
+		return fmt.Sprintf("%d to %d %s", a.Min, a.Max, noun)
+	}
 }
 
 type SymFunction struct {
-	Declaration *FunctionStmt
-	Closure     *Environment
+	Declaration   *FunctionStmt
+	Closure       *Environment
+	IsInitializer bool
 }
 
-func NewSymFunction(declaration *FunctionStmt, closure *Environment) *SymFunction {
+func NewSymFunction(declaration *FunctionStmt, closure *Environment, isInitializer bool) *SymFunction {
 	return &SymFunction{
-		Declaration: declaration,
-		Closure:     closure,
+		Declaration:   declaration,
+		Closure:       closure,
+		IsInitializer: isInitializer,
+	}
+}
+
+func (sf SymFunction) Arity() Arity {
+	declaration := sf.Declaration
+	required := 0
+	for required < len(declaration.Params) && declaration.Defaults[required] == nil {
+		required++
+	}
+	if declaration.Rest != nil {
+		return NewArity(required, -1)
+	}
+	return NewArity(required, len(declaration.Params))
+}
+
+func (sf SymFunction) Parameters() []string {
+	params := make([]string, len(sf.Declaration.Params))
+	for i, param := range sf.Declaration.Params {
+		params[i] = param.Lexeme
+	}
+	return params
+}
+
+func (sf SymFunction) Signature() string {
+	declaration := sf.Declaration
+	var params []string
+	for i, param := range declaration.Params {
+		if declaration.Defaults[i] != nil {
+			params = append(params, param.Lexeme+"?")
+		} else {
+			params = append(params, param.Lexeme)
+		}
+	}
+	if declaration.Rest != nil {
+		params = append(params, ELLIPSIS+declaration.Rest.Lexeme)
 	}
+	return fmt.Sprintf("%s(%s)", declaration.Name.Lexeme, strings.Join(params, ", "))
 }
 
-func (sf SymFunction) Arity() int {
-	return len(sf.Declaration.Params)
+// Call makes the tail calls that the function returns without growing the stack.
+func (sf SymFunction) Call(interpreter *Interpreter, arguments []interface{}) interface{} {
+	function := &sf
+	for {
+		if interpreter.resolution.isGenerator(function.Declaration) {
+			return NewSymGenerator(interpreter, function, arguments)
+		}
+		value := function.run(interpreter, arguments)
+		tailCall, ok := value.(*TailCall)
+		if !ok {
+			return value
+		}
+		function, arguments = tailCall.Function, tailCall.Arguments
+	}
 }
 
-func (sf SymFunction) Call(interpreter *Interpreter, arguments []interface{}) (returnValue interface{}) {
+func (sf SymFunction) run(interpreter *Interpreter, arguments []interface{}) (returnValue interface{}) {
 	envlosingEnvironment := interpreter.environment
 	environment := NewEnvironmentWithEnclosing(sf.Closure)
 	defer func() {
@@ -32,20 +126,55 @@ func (sf SymFunction) Call(interpreter *Interpreter, arguments []interface{}) (r
 				panic(err)
 			}
 			returnValue = symReturn.Value
+			if sf.IsInitializer {
+				returnValue = sf.Closure.getAt(0, THIS)
+			}
 			interpreter.environment = envlosingEnvironment
 			return
 		}
 	}()
-	for i, argument := range sf.Declaration.Params {
-		environment.define(argument.Lexeme, arguments[i])
-	}
+	sf.bindParameters(interpreter, environment, arguments)
 	interpreter.executeBlock(sf.Declaration.Body, environment)
+	if sf.IsInitializer {
+		return sf.Closure.getAt(0, THIS)
+	}
 	return interpreter.currentValue
 }
 
-func (sf SymFunction) bind() *SymFunction {
+// bindParameters evaluates defaults in the environment of the call.
+func (sf SymFunction) bindParameters(interpreter *Interpreter, environment *Environment, arguments []interface{}) {
+	previous := interpreter.environment
+	defer func() {
+		interpreter.environment = previous
+	}()
+	interpreter.environment = environment
+	for i, param := range sf.Declaration.Params {
+		if i < len(arguments) && arguments[i] != missing {
+			environment.define(param.Lexeme, arguments[i])
+		} else {
+			environment.define(param.Lexeme, interpreter.evaluate(sf.Declaration.Defaults[i]))
+		}
+	}
+	if sf.Declaration.Rest != nil {
+		rest := []interface{}{}
+		if len(arguments) > len(sf.Declaration.Params) {
+			rest = append(rest, arguments[len(sf.Declaration.Params):]...)
+		}
+		environment.define(sf.Declaration.Rest.Lexeme, NewSymList(rest))
+	}
+}
+
+func (sf SymFunction) String() string {
+	if sf.Declaration.Name.TokenType == FUNC {
+		return fmt.Sprintf("<%s>", FUNC)
+	}
+	return fmt.Sprintf("<%s %s>", FUNC, sf.Declaration.Name.Lexeme)
+}
+
+func (sf SymFunction) bind(instance *SymInstance) *SymFunction {
 	environment := NewEnvironmentWithEnclosing(sf.Closure)
-	return NewSymFunction(sf.Declaration, environment)
+	environment.define(THIS, instance)
+	return NewSymFunction(sf.Declaration, environment, sf.IsInitializer)
 }
 
 type SymReturn struct {
@@ -55,3 +184,16 @@ type SymReturn struct {
 func NewSymReturn(value interface{}) *SymReturn {
 	return &SymReturn{Value: value}
 }
+
+// TailCall is a call in tail position, left for the caller to make.
+type TailCall struct {
+	Function  *SymFunction
+	Arguments []interface{}
+}
+
+func NewTailCall(function *SymFunction, arguments []interface{}) *TailCall {
+	return &TailCall{
+		Function:  function,
+		Arguments: arguments,
+	}
+}
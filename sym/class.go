@@ -0,0 +1,137 @@
+package sym
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type SymClass struct {
+	Name       string
+	Superclass *SymClass
+	Fields     []*VarStmt
+	Methods    map[string]*SymFunction
+	closure    *Environment
+}
+
+func NewSymClass(name string, superclass *SymClass, fields []*VarStmt, methods map[string]*SymFunction, closure *Environment) *SymClass {
+	return &SymClass{
+		Name:       name,
+		Superclass: superclass,
+		Fields:     fields,
+		Methods:    methods,
+		closure:    closure,
+	}
+}
+
+func (sc *SymClass) findMethod(name string) *SymFunction {
+	method, ok := sc.Methods[name]
+	if ok {
+		return method
+	}
+	if sc.Superclass != nil {
+		return sc.Superclass.findMethod(name)
+	}
+	return nil
+}
+
+func (sc *SymClass) Arity() Arity {
+	initializer := sc.findMethod("init")
+	if initializer == nil {
+		return NewArity(0, 0)
+	}
+	return initializer.Arity()
+}
+
+func (sc *SymClass) Parameters() []string {
+	initializer := sc.findMethod("init")
+	if initializer == nil {
+		return nil
+	}
+	return initializer.Parameters()
+}
+
+func (sc *SymClass) Signature() string {
+	initializer := sc.findMethod("init")
+	if initializer == nil {
+		return sc.Name + "()"
+	}
+	signature := initializer.Signature()
+	return sc.Name + signature[strings.Index(signature, "("):]
+}
+
+func (sc *SymClass) Call(interpreter *Interpreter, arguments []interface{}) interface{} {
+	instance := NewSymInstance(sc)
+	sc.initializeFields(interpreter, instance)
+	initializer := sc.findMethod("init")
+	if initializer != nil {
+		initializer.bind(instance).Call(interpreter, arguments)
+	}
+	return instance
+}
+
+// initializeFields runs the field initializers, superclasses first.
+func (sc *SymClass) initializeFields(interpreter *Interpreter, instance *SymInstance) {
+	if sc.Superclass != nil {
+		sc.Superclass.initializeFields(interpreter, instance)
+	}
+	environment := NewEnvironmentWithEnclosing(sc.closure)
+	environment.define(THIS, instance)
+	previous := interpreter.environment
+	defer func() {
+		interpreter.environment = previous
+	}()
+	interpreter.environment = environment
+	for _, field := range sc.Fields {
+		var value interface{}
+		if field.Initializer != nil {
+			value = interpreter.evaluate(field.Initializer)
+		}
+		instance.define(field.Name.Lexeme, value)
+	}
+}
+
+func (sc *SymClass) String() string {
+	return fmt.Sprintf("<%s %s>", CLASS, sc.Name)
+}
+
+type SymInstance struct {
+	Class  *SymClass
+	Fields map[string]interface{}
+	lock   sync.RWMutex
+}
+
+func NewSymInstance(class *SymClass) *SymInstance {
+	return &SymInstance{
+		Class:  class,
+		Fields: make(map[string]interface{}),
+	}
+}
+
+func (si *SymInstance) get(name Token) interface{} {
+	si.lock.RLock()
+	value, ok := si.Fields[name.Lexeme]
+	si.lock.RUnlock()
+	if ok {
+		return value
+	}
+	method := si.Class.findMethod(name.Lexeme)
+	if method != nil {
+		return method.bind(si)
+	}
+	panic(NewRuntimeError(PROPERTYERROR, name.Line, fmt.Sprintf("Undefined property '%s'.", name.Lexeme)))
+}
+
+func (si *SymInstance) set(name Token, value interface{}) {
+	si.define(name.Lexeme, value)
+}
+
+func (si *SymInstance) define(name string, value interface{}) {
+	si.lock.Lock()
+	defer si.lock.Unlock()
+	si.Fields[name] = value
+}
+
+func (si *SymInstance) String() string {
+	return fmt.Sprintf("<%s instance>", si.Class.Name)
+}
@@ -0,0 +1,291 @@
+package sym
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type SymIterator interface {
+	Next() (interface{}, bool)
+}
+
+type SymList struct {
+	Elements []interface{}
+	lock     sync.RWMutex
+}
+
+func NewSymList(elements []interface{}) *SymList {
+	return &SymList{
+		Elements: elements,
+	}
+}
+
+func (sl *SymList) get(index int) interface{} {
+	sl.lock.RLock()
+	defer sl.lock.RUnlock()
+	sl.checkIndex(index)
+	return sl.Elements[index]
+}
+
+func (sl *SymList) set(index int, value interface{}) {
+	sl.lock.Lock()
+	defer sl.lock.Unlock()
+	sl.checkIndex(index)
+	sl.Elements[index] = value
+}
+
+func (sl *SymList) append(value interface{}) {
+	sl.lock.Lock()
+	defer sl.lock.Unlock()
+	sl.Elements = append(sl.Elements, value)
+}
+
+func (sl *SymList) length() int {
+	sl.lock.RLock()
+	defer sl.lock.RUnlock()
+	return len(sl.Elements)
+}
+
+// elements returns a copy of the elements that other tasks can't change.
+func (sl *SymList) elements() []interface{} {
+	sl.lock.RLock()
+	defer sl.lock.RUnlock()
+	return append([]interface{}{}, sl.Elements...)
+}
+
+func (sl *SymList) checkIndex(index int) {
+	if index < 0 || index >= len(sl.Elements) {
+		panic(NewRuntimeError(INDEXERROR, 0, fmt.Sprintf("List index %d out of range for length %d.", index, len(sl.Elements))))
+	}
+}
+
+func (sl *SymList) iterator() SymIterator {
+	return &listIterator{list: sl}
+}
+
+func (sl *SymList) String() string {
+	return sl.format(make(map[interface{}]bool))
+}
+
+func (sl *SymList) format(visiting map[interface{}]bool) string {
+	if visiting[sl] {
+		return "[...]"
+	}
+	visiting[sl] = true
+	defer delete(visiting, sl)
+	values := sl.elements()
+	elements := make([]string, len(values))
+	for i, element := range values {
+		elements[i] = formatElement(element, visiting)
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+type listIterator struct {
+	list  *SymList
+	index int
+}
+
+func (li *listIterator) Next() (interface{}, bool) {
+	li.list.lock.RLock()
+	defer li.list.lock.RUnlock()
+	if li.index >= len(li.list.Elements) {
+		return nil, false
+	}
+	value := li.list.Elements[li.index]
+	li.index++
+	return value, true
+}
+
+type SymMap struct {
+	Entries map[interface{}]interface{}
+	lock    sync.RWMutex
+}
+
+func NewSymMap() *SymMap {
+	return &SymMap{
+		Entries: make(map[interface{}]interface{}),
+	}
+}
+
+func (sm *SymMap) get(key interface{}) interface{} {
+	entryKey := mapKey(key)
+	sm.lock.RLock()
+	defer sm.lock.RUnlock()
+	return sm.Entries[entryKey]
+}
+
+func (sm *SymMap) set(key interface{}, value interface{}) {
+	entryKey := mapKey(key)
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	sm.Entries[entryKey] = value
+}
+
+func (sm *SymMap) has(key interface{}) bool {
+	entryKey := mapKey(key)
+	sm.lock.RLock()
+	defer sm.lock.RUnlock()
+	_, ok := sm.Entries[entryKey]
+	return ok
+}
+
+func (sm *SymMap) remove(key interface{}) interface{} {
+	entryKey := mapKey(key)
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	value := sm.Entries[entryKey]
+	delete(sm.Entries, entryKey)
+	return value
+}
+
+func (sm *SymMap) length() int {
+	sm.lock.RLock()
+	defer sm.lock.RUnlock()
+	return len(sm.Entries)
+}
+
+// entries returns a copy of the entries that other tasks can't change.
+func (sm *SymMap) entries() map[interface{}]interface{} {
+	sm.lock.RLock()
+	defer sm.lock.RUnlock()
+	entries := make(map[interface{}]interface{}, len(sm.Entries))
+	for key, value := range sm.Entries {
+		entries[key] = value
+	}
+	return entries
+}
+
+func (sm *SymMap) keys() []interface{} {
+	return sortedKeys(sm.entries())
+}
+
+// sortedKeys returns the keys of entries in a stable order.
+func sortedKeys(entries map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, keyValue(key))
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return keyLess(keys[a], keys[b])
+	})
+	return keys
+}
+
+func (sm *SymMap) iterator() SymIterator {
+	return &listIterator{list: NewSymList(sm.keys())}
+}
+
+func (sm *SymMap) String() string {
+	return sm.format(make(map[interface{}]bool))
+}
+
+func (sm *SymMap) format(visiting map[interface{}]bool) string {
+	if visiting[sm] {
+		return "[...]"
+	}
+	visiting[sm] = true
+	defer delete(visiting, sm)
+	values := sm.entries()
+	if len(values) == 0 {
+		return "[→]"
+	}
+	keys := sortedKeys(values)
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		entries[i] = formatElement(key, visiting) + " → " + formatElement(values[mapKey(key)], visiting)
+	}
+	return "[" + strings.Join(entries, ", ") + "]"
+}
+
+// bigKey stands in for a *big.Int key, which would be compared by pointer.
+type bigKey string
+
+// mapKey makes numbers that are equal with '=' share an entry.
+func mapKey(key interface{}) interface{} {
+	switch key := key.(type) {
+	case bool, int64, string:
+		return key
+	case float64:
+		if key == math.Trunc(key) && key >= math.MinInt64 && key < math.MaxInt64 {
+			return int64(key)
+		}
+		return key
+	case *big.Int:
+		return bigKey(key.String())
+	default:
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Map keys must be booleans, numbers or strings, got %v.", key)))
+	}
+}
+
+func keyValue(key interface{}) interface{} {
+	value, ok := key.(bigKey)
+	if ok {
+		number, _ := new(big.Int).SetString(string(value), 10)
+		return number
+	}
+	return key
+}
+
+func keyRank(key interface{}) int {
+	switch key.(type) {
+	case bool:
+		return 0
+	case string:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func keyLess(left interface{}, right interface{}) bool {
+	if keyRank(left) != keyRank(right) {
+		return keyRank(left) < keyRank(right)
+	}
+	switch left := left.(type) {
+	case bool:
+		return !left && right.(bool)
+	case string:
+		return left < right.(string)
+	default:
+		return compareNumbers(left, right) < 0
+	}
+}
+
+type stringIterator struct {
+	runes []rune
+	index int
+}
+
+func (si *stringIterator) Next() (interface{}, bool) {
+	if si.index >= len(si.runes) {
+		return nil, false
+	}
+	value := string(si.runes[si.index])
+	si.index++
+	return value, true
+}
+
+func stringifyElement(value interface{}) string {
+	return formatElement(value, make(map[interface{}]bool))
+}
+
+// formatter is a value that can hold itself, and prints "..." where it does.
+type formatter interface {
+	format(visiting map[interface{}]bool) string
+}
+
+func formatElement(value interface{}, visiting map[interface{}]bool) string {
+	switch value := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", value)
+	case formatter:
+		return value.format(visiting)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
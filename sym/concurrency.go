@@ -0,0 +1,339 @@
+package sym
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+)
+
+// scheduler guards channels and tasks, and counts the tasks that aren't
+// waiting so that it can tell when none of them can go on.
+type scheduler struct {
+	lock    sync.Mutex
+	changed *sync.Cond
+	running int
+	waiting int
+	failed  []*SymTask
+}
+
+func newScheduler() *scheduler {
+	scheduler := &scheduler{running: 1}
+	scheduler.changed = sync.NewCond(&scheduler.lock)
+	return scheduler
+}
+
+func (s *scheduler) start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.running++
+}
+
+// wait blocks until ready holds, or raises a ChannelError when every other
+// task is waiting too. The caller holds the lock.
+func (s *scheduler) wait(ready func() bool) {
+	for !ready() {
+		if s.running == 1 {
+			panic(NewRuntimeError(CHANNELERROR, 0, "Deadlock: every task is waiting."))
+		}
+		s.running--
+		s.waiting++
+		s.changed.Wait()
+	}
+}
+
+// unwaited returns the tasks that have failed without being waited for
+// since it was last called.
+func (s *scheduler) unwaited() []*SymTask {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var tasks []*SymTask
+	for _, task := range s.failed {
+		if !task.waited {
+			tasks = append(tasks, task)
+		}
+	}
+	s.failed = nil
+	return tasks
+}
+
+// notify wakes the waiting tasks to check whether they can go on.
+func (s *scheduler) notify() {
+	s.running += s.waiting
+	s.waiting = 0
+	s.changed.Broadcast()
+}
+
+type SymTask struct {
+	Name     string
+	Line     int
+	value    interface{}
+	err      interface{}
+	finished bool
+	waited   bool
+}
+
+func NewSymTask(function SymCallable, line int) *SymTask {
+	return &SymTask{
+		Name: function.Signature(),
+		Line: line,
+	}
+}
+
+func (st *SymTask) run(interpreter *Interpreter, function SymCallable, arguments []interface{}) {
+	defer func() {
+		err := recover()
+		scheduler := interpreter.scheduler
+		scheduler.lock.Lock()
+		defer scheduler.lock.Unlock()
+		st.err = err
+		st.finished = true
+		if err != nil {
+			scheduler.failed = append(scheduler.failed, st)
+		}
+		scheduler.running--
+		scheduler.notify()
+	}()
+	st.value = function.Call(interpreter, arguments)
+}
+
+func (st *SymTask) String() string {
+	return fmt.Sprintf("<%s %s>", SPAWN, st.Name)
+}
+
+// SymChannel only carries values of its Type, a name returned by 'type', or "any".
+// Its state is guarded by the scheduler.
+type SymChannel struct {
+	Type     string
+	capacity int
+	buffer   []interface{}
+	senders  []*channelSender
+	closed   bool
+}
+
+// channelSender is a send waiting for a receiver, or for room in the buffer.
+type channelSender struct {
+	value    interface{}
+	received bool
+}
+
+func NewSymChannel(valueType string, capacity int) *SymChannel {
+	return &SymChannel{
+		Type:     valueType,
+		capacity: capacity,
+	}
+}
+
+func (sc *SymChannel) accepts(value interface{}) bool {
+	if sc.Type == "any" || typeName(value) == sc.Type {
+		return true
+	}
+	instance, ok := value.(*SymInstance)
+	if ok {
+		for class := instance.Class.Superclass; class != nil; class = class.Superclass {
+			if class.Name == sc.Type {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (sc *SymChannel) String() string {
+	return fmt.Sprintf("<channel %s>", sc.Type)
+}
+
+// send waits until a receiver takes value, or until the buffer has room.
+func (sc *SymChannel) send(scheduler *scheduler, value interface{}) {
+	scheduler.lock.Lock()
+	defer scheduler.lock.Unlock()
+	if sc.closed {
+		panic(NewRuntimeError(CHANNELERROR, 0, fmt.Sprintf("Can't send on closed %v.", sc)))
+	}
+	if len(sc.buffer) < sc.capacity {
+		sc.buffer = append(sc.buffer, value)
+		scheduler.notify()
+		return
+	}
+	sender := &channelSender{value: value}
+	sc.senders = append(sc.senders, sender)
+	scheduler.notify()
+	defer sc.cancel(sender)
+	scheduler.wait(func() bool {
+		return sender.received || sc.closed
+	})
+	if !sender.received {
+		panic(NewRuntimeError(CHANNELERROR, 0, fmt.Sprintf("Can't send on closed %v.", sc)))
+	}
+}
+
+// receive waits for a value, and reports false once the channel is closed
+// and empty.
+func (sc *SymChannel) receive(scheduler *scheduler) (value interface{}, ok bool) {
+	scheduler.lock.Lock()
+	defer scheduler.lock.Unlock()
+	scheduler.wait(func() bool {
+		var ready bool
+		value, ok, ready = sc.take()
+		return ready
+	})
+	if ok {
+		scheduler.notify()
+	}
+	return value, ok
+}
+
+// take receives a value without waiting. It isn't ready when the channel is
+// open but has no value to give.
+func (sc *SymChannel) take() (value interface{}, ok bool, ready bool) {
+	if len(sc.buffer) > 0 {
+		value, sc.buffer = sc.buffer[0], sc.buffer[1:]
+		if len(sc.senders) > 0 {
+			sc.buffer = append(sc.buffer, sc.senders[0].value)
+			sc.senders[0].received = true
+			sc.senders = sc.senders[1:]
+		}
+		return value, true, true
+	}
+	if len(sc.senders) > 0 {
+		value = sc.senders[0].value
+		sc.senders[0].received = true
+		sc.senders = sc.senders[1:]
+		return value, true, true
+	}
+	return nil, false, sc.closed
+}
+
+func (sc *SymChannel) cancel(sender *channelSender) {
+	for index, candidate := range sc.senders {
+		if candidate == sender {
+			sc.senders = append(sc.senders[:index], sc.senders[index+1:]...)
+			return
+		}
+	}
+}
+
+func (sc *SymChannel) close(scheduler *scheduler) {
+	scheduler.lock.Lock()
+	defer scheduler.lock.Unlock()
+	if sc.closed {
+		panic(NewRuntimeError(CHANNELERROR, 0, fmt.Sprintf("Can't close %v twice.", sc)))
+	}
+	sc.closed = true
+	sc.senders = nil
+	scheduler.notify()
+}
+
+type channelIterator struct {
+	interpreter *Interpreter
+	channel     *SymChannel
+}
+
+func (ci *channelIterator) Next() (interface{}, bool) {
+	return ci.channel.receive(ci.interpreter.scheduler)
+}
+
+func nativeChannel(interpreter *Interpreter, arguments []interface{}) interface{} {
+	valueType, ok := arguments[0].(string)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Channel type must be a string, got %v.", arguments[0])))
+	}
+	capacity, ok := arguments[1].(int64)
+	if !ok || capacity < 0 {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Channel capacity must be a non-negative integer, got %v.", arguments[1])))
+	}
+	return NewSymChannel(valueType, int(capacity))
+}
+
+func nativeSend(interpreter *Interpreter, arguments []interface{}) interface{} {
+	channel := toChannel(arguments[0])
+	value := arguments[1]
+	if !channel.accepts(value) {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can't send %v on %v.", stringifyElement(value), channel)))
+	}
+	channel.send(interpreter.scheduler, value)
+	return value
+}
+
+// nativeReceive returns ø once the channel is closed and empty.
+func nativeReceive(interpreter *Interpreter, arguments []interface{}) interface{} {
+	value, _ := toChannel(arguments[0]).receive(interpreter.scheduler)
+	return value
+}
+
+func nativeClose(interpreter *Interpreter, arguments []interface{}) interface{} {
+	toChannel(arguments[0]).close(interpreter.scheduler)
+	return nil
+}
+
+// nativeSelect returns [channel, value], or ø once every channel is closed.
+func nativeSelect(interpreter *Interpreter, arguments []interface{}) interface{} {
+	list, ok := arguments[0].(*SymList)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can only select from a list of channels, got %v.", arguments[0])))
+	}
+	var channels []*SymChannel
+	for _, element := range list.elements() {
+		channels = append(channels, toChannel(element))
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+	scheduler := interpreter.scheduler
+	scheduler.lock.Lock()
+	defer scheduler.lock.Unlock()
+	var selected *SymList
+	scheduler.wait(func() bool {
+		open := false
+		start := rand.Intn(len(channels))
+		for index := range channels {
+			channel := channels[(start+index)%len(channels)]
+			value, ok, ready := channel.take()
+			if ok {
+				selected = NewSymList([]interface{}{channel, value})
+				return true
+			}
+			open = open || !ready
+		}
+		return !open
+	})
+	if selected == nil {
+		return nil
+	}
+	scheduler.notify()
+	return selected
+}
+
+func nativeWait(interpreter *Interpreter, arguments []interface{}) interface{} {
+	task, ok := arguments[0].(*SymTask)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can only wait for a task, got %v.", arguments[0])))
+	}
+	scheduler := interpreter.scheduler
+	scheduler.lock.Lock()
+	defer scheduler.lock.Unlock()
+	scheduler.wait(func() bool {
+		return task.finished
+	})
+	task.waited = true
+	if task.err != nil {
+		panic(task.err)
+	}
+	return task.value
+}
+
+func toChannel(value interface{}) *SymChannel {
+	channel, ok := value.(*SymChannel)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Expected a channel, got %v.", value)))
+	}
+	return channel
+}
+
+// reportFailedTasks prints the errors of tasks that nothing waited for, which
+// would otherwise be lost.
+func (i *Interpreter) reportFailedTasks() {
+	for _, task := range i.scheduler.unwaited() {
+		fmt.Fprintf(os.Stderr, "Task %v from line %d failed: %v\n", task, task.Line, task.err)
+	}
+}
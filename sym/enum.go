@@ -0,0 +1,122 @@
+package sym
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SymEnum struct {
+	Name     string
+	Variants map[string]interface{}
+}
+
+func NewSymEnum(statement *EnumStmt) *SymEnum {
+	enum := &SymEnum{
+		Name:     statement.Name.Lexeme,
+		Variants: make(map[string]interface{}),
+	}
+	for _, declaration := range statement.Variants {
+		var fields []string
+		if declaration.Fields != nil {
+			fields = []string{}
+		}
+		for _, field := range declaration.Fields {
+			fields = append(fields, field.Lexeme)
+		}
+		constructor := NewVariantConstructor(enum, declaration.Name.Lexeme, fields)
+		if declaration.Fields == nil {
+			enum.Variants[declaration.Name.Lexeme] = NewSymVariant(constructor, nil)
+		} else {
+			enum.Variants[declaration.Name.Lexeme] = constructor
+		}
+	}
+	return enum
+}
+
+func (se *SymEnum) get(name Token) interface{} {
+	variant, ok := se.Variants[name.Lexeme]
+	if !ok {
+		panic(NewRuntimeError(PROPERTYERROR, name.Line, fmt.Sprintf("Undefined variant '%s' in '%s'.", name.Lexeme, se.Name)))
+	}
+	return variant
+}
+
+func (se *SymEnum) String() string {
+	return fmt.Sprintf("<∑ %s>", se.Name)
+}
+
+type VariantConstructor struct {
+	Enum   *SymEnum
+	Name   string
+	Fields []string
+}
+
+func NewVariantConstructor(enum *SymEnum, name string, fields []string) *VariantConstructor {
+	return &VariantConstructor{
+		Enum:   enum,
+		Name:   name,
+		Fields: fields,
+	}
+}
+
+func (vc *VariantConstructor) Arity() Arity {
+	return NewArity(len(vc.Fields), len(vc.Fields))
+}
+
+func (vc *VariantConstructor) Call(interpreter *Interpreter, arguments []interface{}) interface{} {
+	return NewSymVariant(vc, append([]interface{}{}, arguments...))
+}
+
+func (vc *VariantConstructor) Parameters() []string {
+	return vc.Fields
+}
+
+func (vc *VariantConstructor) Signature() string {
+	return fmt.Sprintf("%s.%s(%s)", vc.Enum.Name, vc.Name, strings.Join(vc.Fields, ", "))
+}
+
+func (vc *VariantConstructor) String() string {
+	return fmt.Sprintf("<ƒ %s.%s>", vc.Enum.Name, vc.Name)
+}
+
+type SymVariant struct {
+	Constructor *VariantConstructor
+	Values      []interface{}
+}
+
+func NewSymVariant(constructor *VariantConstructor, values []interface{}) *SymVariant {
+	return &SymVariant{
+		Constructor: constructor,
+		Values:      values,
+	}
+}
+
+func (sv *SymVariant) get(name Token) interface{} {
+	for index, field := range sv.Constructor.Fields {
+		if field == name.Lexeme {
+			return sv.Values[index]
+		}
+	}
+	panic(NewRuntimeError(PROPERTYERROR, name.Line, fmt.Sprintf("Undefined field '%s' in %s.", name.Lexeme, sv.Constructor.Signature())))
+}
+
+func (sv *SymVariant) String() string {
+	return sv.format(make(map[interface{}]bool))
+}
+
+func (sv *SymVariant) format(visiting map[interface{}]bool) string {
+	name := sv.Constructor.Enum.Name + "." + sv.Constructor.Name
+	if sv.Constructor.Fields == nil {
+		return name
+	}
+	if visiting[sv] {
+		return name + "(...)"
+	}
+	visiting[sv] = true
+	defer delete(visiting, sv)
+	values := make([]string, len(sv.Values))
+	for index, value := range sv.Values {
+		values[index] = formatElement(value, visiting)
+	}
+	return name + "(" + strings.Join(values, ", ") + ")"
+}
@@ -1,14 +1,19 @@
 package sym
 
+import "sync"
+
 type Environment struct {
 	enclosing *Environment
 	values    map[string]interface{}
+	constants map[string]bool
+	lock      sync.RWMutex
 }
 
 func NewEnvironment() *Environment {
 	return &Environment{
 		enclosing: nil,
 		values:    make(map[string]interface{}),
+		constants: make(map[string]bool),
 	}
 }
 
@@ -16,6 +21,7 @@ func NewEnvironmentWithEnclosing(enclosing *Environment) *Environment {
 	return &Environment{
 		enclosing: enclosing,
 		values:    make(map[string]interface{}),
+		constants: make(map[string]bool),
 	}
 }
 
@@ -27,23 +33,51 @@ func (e *Environment) ancestor(distance int) *Environment {
 	return environment
 }
 
+func (e *Environment) root() *Environment {
+	environment := e
+	for environment.enclosing != nil {
+		environment = environment.enclosing
+	}
+	return environment
+}
+
 func (e *Environment) assign(name string, value interface{}) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
 	e.values[name] = value
 }
 
 func (e *Environment) assignAt(distance int, name string, value interface{}) {
-	e.ancestor(distance).values[name] = value
+	e.ancestor(distance).assign(name, value)
 }
 
 func (e *Environment) define(name string, value interface{}) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.values[name] = value
+}
+
+func (e *Environment) defineConstant(name string, value interface{}) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
 	e.values[name] = value
+	e.constants[name] = true
+}
+
+func (e *Environment) isConstant(name string) bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.constants[name]
 }
 
 func (e *Environment) get(name string) (interface{}, bool) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
 	value, ok := e.values[name]
 	return value, ok
 }
 
 func (e *Environment) getAt(distance int, name string) interface{} {
-	return e.ancestor(distance).values[name]
+	value, _ := e.ancestor(distance).get(name)
+	return value
 }
@@ -0,0 +1,71 @@
+package sym
+
+import "fmt"
+
+const (
+	ARGUMENTERROR = "ArgumentError"
+	CHANNELERROR  = "ChannelError"
+	CONSTANTERROR = "ConstantError"
+	DIVISIONERROR = "DivisionByZero"
+	IMPORTERROR   = "ImportError"
+	INDEXERROR    = "IndexError"
+	MATCHERROR    = "MatchError"
+	NAMEERROR     = "NameError"
+	OVERFLOWERROR = "OverflowError"
+	PROPERTYERROR = "PropertyError"
+	TYPEERROR     = "TypeError"
+)
+
+type RuntimeError struct {
+	Kind    string
+	Message string
+	Line    int
+}
+
+func NewRuntimeError(kind string, line int, message string) *RuntimeError {
+	return &RuntimeError{
+		Kind:    kind,
+		Message: message,
+		Line:    line,
+	}
+}
+
+func (re *RuntimeError) get(name Token) interface{} {
+	switch name.Lexeme {
+	case "message":
+		return re.Message
+	case "kind":
+		return re.Kind
+	case "line":
+		return int64(re.Line)
+	}
+	panic(NewRuntimeError(PROPERTYERROR, name.Line, fmt.Sprintf("Undefined property '%s'.", name.Lexeme)))
+}
+
+func (re *RuntimeError) Error() string {
+	if re.Line == 0 {
+		return fmt.Sprintf("%s: %s", re.Kind, re.Message)
+	}
+	return fmt.Sprintf("%s: %s [line %d]", re.Kind, re.Message, re.Line)
+}
+
+func (re *RuntimeError) String() string {
+	return re.Error()
+}
+
+// SymThrow carries a value thrown with '↯' up to the nearest '⚐'.
+type SymThrow struct {
+	Value interface{}
+	Line  int
+}
+
+func NewSymThrow(value interface{}, line int) *SymThrow {
+	return &SymThrow{
+		Value: value,
+		Line:  line,
+	}
+}
+
+func (st *SymThrow) Error() string {
+	return fmt.Sprintf("Uncaught %v [line %d]", st.Value, st.Line)
+}
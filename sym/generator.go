@@ -0,0 +1,126 @@
+package sym
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SymGenerator runs the body of a function containing '↳' on its own goroutine.
+type SymGenerator struct {
+	function    *SymFunction
+	arguments   []interface{}
+	interpreter *Interpreter
+	resume      chan struct{}
+	results     chan generatorResult
+	started     bool
+	running     bool
+	closing     bool
+	done        bool
+	lock        sync.Mutex
+}
+
+// generatorClosed unwinds the body of a generator that is being closed.
+type generatorClosed struct{}
+
+type generatorResult struct {
+	value interface{}
+	done  bool
+	err   interface{}
+}
+
+func NewSymGenerator(interpreter *Interpreter, function *SymFunction, arguments []interface{}) *SymGenerator {
+	generator := &SymGenerator{
+		function:  function,
+		arguments: arguments,
+		resume:    make(chan struct{}),
+		results:   make(chan generatorResult),
+	}
+	generator.interpreter = interpreter.fork()
+	generator.interpreter.generator = generator
+	return generator
+}
+
+func (sg *SymGenerator) Next() (interface{}, bool) {
+	if !sg.claim() {
+		return nil, false
+	}
+	sg.resume <- struct{}{}
+	result := <-sg.results
+	sg.lock.Lock()
+	sg.running = false
+	sg.done = result.done || result.err != nil
+	sg.lock.Unlock()
+	if result.err != nil {
+		panic(result.err)
+	}
+	if result.done {
+		return nil, false
+	}
+	return result.value, true
+}
+
+// claim marks the generator as running, unless it is done.
+func (sg *SymGenerator) claim() bool {
+	sg.lock.Lock()
+	defer sg.lock.Unlock()
+	if sg.done {
+		return false
+	}
+	if sg.running {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can't resume %v while it is running.", sg)))
+	}
+	if !sg.started {
+		sg.started = true
+		go sg.run()
+	}
+	sg.running = true
+	return true
+}
+
+func (sg *SymGenerator) run() {
+	<-sg.resume
+	defer func() {
+		err := recover()
+		if _, ok := err.(generatorClosed); ok {
+			err = nil
+		}
+		sg.results <- generatorResult{done: true, err: err}
+	}()
+	value := sg.function.run(sg.interpreter, sg.arguments)
+	tailCall, ok := value.(*TailCall)
+	if ok {
+		tailCall.Function.Call(sg.interpreter, tailCall.Arguments)
+	}
+}
+
+// Close unwinds a generator that won't be resumed, running its '∎' blocks.
+func (sg *SymGenerator) Close() {
+	sg.lock.Lock()
+	if !sg.started || sg.running || sg.done {
+		sg.lock.Unlock()
+		return
+	}
+	sg.done = true
+	sg.closing = true
+	sg.lock.Unlock()
+	sg.resume <- struct{}{}
+	result := <-sg.results
+	if result.err != nil {
+		panic(result.err)
+	}
+}
+
+func (sg *SymGenerator) yield(value interface{}) {
+	if sg.closing {
+		panic(generatorClosed{})
+	}
+	sg.results <- generatorResult{value: value}
+	<-sg.resume
+	if sg.closing {
+		panic(generatorClosed{})
+	}
+}
+
+func (sg *SymGenerator) String() string {
+	return fmt.Sprintf("<%s %s>", YIELD, sg.function.Declaration.Name.Lexeme)
+}
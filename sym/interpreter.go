@@ -1,21 +1,36 @@
 package sym
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
 
 type Interpreter struct {
 	currentValue interface{}
 	environment  *Environment
-	globals      *Environment
-	locals       map[Expr]int
+	resolution   *resolution
+	generator    *SymGenerator
+	scheduler    *scheduler
+	module       *SymModule
+	modules      map[string]*SymModule
+	importing    []string
+	searchPath   []string
 }
 
 func NewInterpreter() *Interpreter {
 	globals := NewEnvironment()
+	defineNatives(globals)
 	return &Interpreter{
 		currentValue: nil,
 		environment:  globals,
-		globals:      globals,
-		locals:       make(map[Expr]int),
+		resolution:   newResolution(),
+		scheduler:    newScheduler(),
+		module:       NewSymModule("", "", globals),
+		modules:      make(map[string]*SymModule),
+		importing:    make([]string, 0),
+		searchPath:   make([]string, 0),
 	}
 }
 
@@ -41,26 +56,86 @@ func (i *Interpreter) execute(statement Stmt) interface{} {
 }
 
 func (i Interpreter) resolve(expression Expr, depth int) {
-	i.locals[expression] = depth
+	i.resolution.Lock()
+	defer i.resolution.Unlock()
+	i.resolution.locals[expression] = depth
+}
+
+func (i Interpreter) resolveTail(statement *ReturnStmt) {
+	i.resolution.Lock()
+	defer i.resolution.Unlock()
+	i.resolution.tails[statement] = true
+}
+
+func (i Interpreter) resolveGenerator(declaration *FunctionStmt) {
+	i.resolution.Lock()
+	defer i.resolution.Unlock()
+	i.resolution.generators[declaration] = true
+}
+
+// resolution holds what the resolver found, which it can add to while tasks
+// read it when a module is imported.
+type resolution struct {
+	sync.RWMutex
+	locals     map[Expr]int
+	tails      map[*ReturnStmt]bool
+	generators map[*FunctionStmt]bool
+}
+
+func newResolution() *resolution {
+	return &resolution{
+		locals:     make(map[Expr]int),
+		tails:      make(map[*ReturnStmt]bool),
+		generators: make(map[*FunctionStmt]bool),
+	}
+}
+
+func (r *resolution) local(expression Expr) (int, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	distance, ok := r.locals[expression]
+	return distance, ok
+}
+
+func (r *resolution) isTail(statement *ReturnStmt) bool {
+	r.RLock()
+	defer r.RUnlock()
+	return r.tails[statement]
+}
+
+func (r *resolution) isGenerator(declaration *FunctionStmt) bool {
+	r.RLock()
+	defer r.RUnlock()
+	return r.generators[declaration]
+}
+
+// fork returns an Interpreter for a task or the body of a generator.
+func (i *Interpreter) fork() *Interpreter {
+	forked := *i
+	forked.currentValue = nil
+	forked.generator = nil
+	return &forked
+}
+
+// globals returns the top-level environment of the running module.
+func (i *Interpreter) globals() *Environment {
+	return i.environment.root()
 }
 
 func (i *Interpreter) executeBlock(statements []Stmt, environment *Environment) {
 	previous := i.environment
+	defer func() {
+		i.environment = previous
+	}()
 	i.environment = environment
 	for _, statement := range statements {
 		i.execute(statement)
 	}
-	i.environment = previous
 }
 
 func (i *Interpreter) visitAssignExpr(expression *AssignExpr) interface{} {
 	value := i.evaluate(expression.Value)
-	distance, ok := i.locals[expression]
-	if ok {
-		i.environment.assignAt(distance, expression.Name.Lexeme, value)
-	} else {
-		i.globals.assign(expression.Name.Lexeme, value)
-	}
+	i.assignVariable(expression.Name, expression, value)
 	return value
 }
 
@@ -72,65 +147,223 @@ func (i *Interpreter) visitBinaryExpr(expression *BinaryExpr) interface{} {
 		return !i.isEqual(left, right)
 	case EQUAL:
 		return i.isEqual(left, right)
+	case IN:
+		return i.contains(expression.Operator, right, left)
 	case PLUS:
-		switch t := left.(type) {
-		case float64:
-			rightValue, rightOk := right.(float64)
-			if rightOk {
-				return t + rightValue
-			}
-		case string:
-			rightValue, rightOk := right.(string)
-			if rightOk {
-				return t + rightValue
-			}
-		default:
-			panic(fmt.Sprintf("Operands must be two numbers or two strings, got %v and %v.", left, right))
-		}
+		return i.add(expression.Operator, left, right)
+	case MINUS, DIVIDE, MULTIPLY, MODULO, FLOORDIVIDE, POWER:
+		return i.arithmetic(expression.Operator, left, right)
 	default:
-		leftValue, leftOk := left.(float64)
-		rightValue, rightOk := right.(float64)
-		if !leftOk || !rightOk {
-			panic(fmt.Sprintf("Operands must be two numbers, got %v and %v.", left, right))
+		if !isNumber(left) || !isNumber(right) {
+			panic(NewRuntimeError(TYPEERROR, expression.Operator.Line,
+				fmt.Sprintf("Operands must be two numbers, got %v and %v.", left, right)))
+		}
+		comparison := compareNumbers(left, right)
+		if math.IsNaN(toFloat(left)) || math.IsNaN(toFloat(right)) {
+			return false
 		}
 		switch expression.Operator.TokenType {
-		case MINUS:
-			return leftValue - rightValue
-		case DIVIDE:
-			return leftValue / rightValue
-		case MULTIPLY:
-			return leftValue * rightValue
 		case GREATER:
-			return leftValue > rightValue
+			return comparison > 0
 		case GREATEREQUAL:
-			return leftValue >= rightValue
+			return comparison >= 0
 		case LESS:
-			return leftValue < rightValue
+			return comparison < 0
 		case LESSEQUAL:
-			return leftValue <= rightValue
+			return comparison <= 0
 		}
 	}
 	panic("You done messed up.")
 }
 
+func (i *Interpreter) visitCompoundAssignExpr(expression *CompoundAssignExpr) interface{} {
+	var current, value interface{}
+	switch target := expression.Target.(type) {
+	case *VarExpr:
+		current = i.variableLookup(target.Name, expression)
+		value = i.compound(expression, current)
+		i.assignVariable(target.Name, expression, value)
+	case *IndexExpr:
+		defer i.atLine(target.Bracket)
+		object := i.evaluate(target.Object)
+		index := i.evaluate(target.Index)
+		current = i.getIndex(object, index, target.Bracket)
+		value = i.compound(expression, current)
+		i.setIndex(object, index, value, target.Bracket)
+	case *GetExpr:
+		object := i.evaluate(target.Object)
+		current = i.getProperty(object, target.Name)
+		value = i.compound(expression, current)
+		i.setProperty(object, target.Name, value)
+	default:
+		panic("You done messed up.")
+	}
+	if expression.Postfix {
+		return current
+	}
+	return value
+}
+
+func (i *Interpreter) compound(expression *CompoundAssignExpr, current interface{}) interface{} {
+	value := i.evaluate(expression.Value)
+	if expression.Operator.TokenType == PLUS {
+		return i.add(expression.Operator, current, value)
+	}
+	return i.arithmetic(expression.Operator, current, value)
+}
+
 func (i *Interpreter) visitCallExpr(expression *CallExpr) interface{} {
+	defer i.atLine(expression.Parenthesis)
+	function, arguments := i.callee(expression)
+	return function.Call(i, arguments)
+}
+
+// tailCall returns a call to a Symlang function as a TailCall instead of making it.
+func (i *Interpreter) tailCall(expression *CallExpr) interface{} {
+	defer i.atLine(expression.Parenthesis)
+	function, arguments := i.callee(expression)
+	symFunction, ok := function.(*SymFunction)
+	if ok {
+		return NewTailCall(symFunction, arguments)
+	}
+	return function.Call(i, arguments)
+}
+
+func (i *Interpreter) callee(expression *CallExpr) (SymCallable, []interface{}) {
 	callee := i.evaluate(expression.Callee)
 	var arguments []interface{}
 	for _, argument := range expression.Arguments {
 		argument := i.evaluate(argument)
 		arguments = append(arguments, argument)
 	}
+	var namedArguments []interface{}
+	for _, argument := range expression.NamedArguments {
+		namedArguments = append(namedArguments, i.evaluate(argument))
+	}
 	function, ok := callee.(SymCallable)
 	if !ok {
-		panic("Can only call functions.")
+		panic(NewRuntimeError(TYPEERROR, expression.Parenthesis.Line, fmt.Sprintf("Can only call functions, got %v.", callee)))
+	}
+	if len(expression.Names) > 0 {
+		arguments = i.bindNamed(function, arguments, expression.Names, namedArguments)
+	}
+	arity := function.Arity()
+	if !arity.accepts(len(arguments)) {
+		panic(NewRuntimeError(ARGUMENTERROR, expression.Parenthesis.Line,
+			fmt.Sprintf("%s expects %v but got %d.", function.Signature(), arity, len(arguments))))
+	}
+	return function, arguments
+}
+
+// bindNamed fills optional parameters skipped over by name with missing.
+func (i *Interpreter) bindNamed(function SymCallable, arguments []interface{}, names []Token, values []interface{}) []interface{} {
+	params := function.Parameters()
+	for len(arguments) < len(params) {
+		arguments = append(arguments, missing)
+	}
+	for n, name := range names {
+		position := -1
+		for p, param := range params {
+			if param == name.Lexeme {
+				position = p
+				break
+			}
+		}
+		if position == -1 {
+			panic(NewRuntimeError(ARGUMENTERROR, name.Line,
+				fmt.Sprintf("%s has no parameter named '%s'.", function.Signature(), name.Lexeme)))
+		}
+		if arguments[position] != missing {
+			panic(NewRuntimeError(ARGUMENTERROR, name.Line,
+				fmt.Sprintf("%s got more than one value for '%s'.", function.Signature(), name.Lexeme)))
+		}
+		arguments[position] = values[n]
+	}
+	for p := 0; p < function.Arity().Min; p++ {
+		if arguments[p] == missing {
+			panic(NewRuntimeError(ARGUMENTERROR, names[0].Line,
+				fmt.Sprintf("%s is missing a value for '%s'.", function.Signature(), params[p])))
+		}
 	}
-	if len(arguments) != function.Arity() {
-		panic(fmt.Sprintf("Expected %d arguments but got %d.", function.Arity(), len(arguments)))
+	for len(arguments) > 0 && arguments[len(arguments)-1] == missing {
+		arguments = arguments[:len(arguments)-1]
 	}
-	value := function.Call(i, arguments)
+	return arguments
+}
+
+func (i *Interpreter) visitConditionalExpr(expression *ConditionalExpr) interface{} {
+	if i.isTruthy(i.evaluate(expression.Condition)) {
+		return i.evaluate(expression.Then)
+	}
+	return i.evaluate(expression.Else)
+}
+
+func (i *Interpreter) visitFunctionExpr(expression *FunctionExpr) interface{} {
+	return NewSymFunction(expression.Declaration, i.environment, false)
+}
+
+func (i *Interpreter) visitGetExpr(expression *GetExpr) interface{} {
+	object := i.evaluate(expression.Object)
+	return i.getProperty(object, expression.Name)
+}
+
+func (i *Interpreter) visitIndexExpr(expression *IndexExpr) interface{} {
+	defer i.atLine(expression.Bracket)
+	object := i.evaluate(expression.Object)
+	index := i.evaluate(expression.Index)
+	return i.getIndex(object, index, expression.Bracket)
+}
+
+func (i *Interpreter) visitIndexSetExpr(expression *IndexSetExpr) interface{} {
+	defer i.atLine(expression.Bracket)
+	object := i.evaluate(expression.Object)
+	index := i.evaluate(expression.Index)
+	value := i.evaluate(expression.Value)
+	i.setIndex(object, index, value, expression.Bracket)
 	return value
 }
 
+func (i *Interpreter) visitInterpolationExpr(expression *InterpolationExpr) interface{} {
+	var text strings.Builder
+	for _, part := range expression.Parts {
+		text.WriteString(i.stringify(i.evaluate(part)))
+	}
+	return text.String()
+}
+
+func (i *Interpreter) visitListExpr(expression *ListExpr) interface{} {
+	elements := make([]interface{}, 0, len(expression.Elements))
+	for _, element := range expression.Elements {
+		elements = append(elements, i.evaluate(element))
+	}
+	return NewSymList(elements)
+}
+
+func (i *Interpreter) visitMapExpr(expression *MapExpr) interface{} {
+	defer i.atLine(expression.Bracket)
+	symMap := NewSymMap()
+	for index := range expression.Keys {
+		key := i.evaluate(expression.Keys[index])
+		value := i.evaluate(expression.Values[index])
+		symMap.set(key, value)
+	}
+	return symMap
+}
+
+func (i *Interpreter) visitMatchExpr(expression *MatchExpr) interface{} {
+	subject := i.evaluate(expression.Subject)
+	arm, environment := i.matchArm(expression.Arms, subject)
+	if arm == nil {
+		panic(NewRuntimeError(MATCHERROR, expression.Keyword.Line, fmt.Sprintf("No pattern matched %v.", stringifyElement(subject))))
+	}
+	previous := i.environment
+	defer func() {
+		i.environment = previous
+	}()
+	i.environment = environment
+	return i.evaluate(arm.Value)
+}
+
 func (i *Interpreter) visitLiteralExpr(expression *LiteralExpr) interface{} {
 	return expression.Value
 }
@@ -149,17 +382,47 @@ func (i *Interpreter) visitLogicalExpr(expression *LogicalExpr) interface{} {
 	return i.evaluate(expression.Right)
 }
 
+func (i *Interpreter) visitSetExpr(expression *SetExpr) interface{} {
+	object := i.evaluate(expression.Object)
+	value := i.evaluate(expression.Value)
+	i.setProperty(object, expression.Name, value)
+	return value
+}
+
+func (i *Interpreter) visitSpawnExpr(expression *SpawnExpr) interface{} {
+	defer i.atLine(expression.Call.Parenthesis)
+	function, arguments := i.callee(expression.Call)
+	task := NewSymTask(function, expression.Keyword.Line)
+	i.scheduler.start()
+	go task.run(i.fork(), function, arguments)
+	return task
+}
+
+func (i *Interpreter) visitSuperExpr(expression *SuperExpr) interface{} {
+	distance, _ := i.resolution.local(expression)
+	superclass := i.environment.getAt(distance, SUPER).(*SymClass)
+	instance := i.environment.getAt(distance-1, THIS).(*SymInstance)
+	method := superclass.findMethod(expression.Method.Lexeme)
+	if method == nil {
+		panic(NewRuntimeError(PROPERTYERROR, expression.Method.Line, fmt.Sprintf("Undefined property '%s'.", expression.Method.Lexeme)))
+	}
+	return method.bind(instance)
+}
+
+func (i *Interpreter) visitThisExpr(expression *ThisExpr) interface{} {
+	return i.variableLookup(expression.Keyword, expression)
+}
+
 func (i *Interpreter) visitUnaryExpr(expression *UnaryExpr) interface{} {
 	right := i.evaluate(expression.Right)
 	switch expression.Operator.TokenType {
 	case BANG:
 		return !i.isTruthy(right)
 	case MINUS:
-		value, ok := right.(float64)
-		if ok {
-			return -value
+		if isNumber(right) {
+			return negate(right)
 		}
-		panic(fmt.Sprintf("Operand must be a number, got %v.", expression.Right))
+		panic(NewRuntimeError(TYPEERROR, expression.Operator.Line, fmt.Sprintf("Operand must be a number, got %v.", right)))
 	default:
 		panic("You done messed up.")
 	}
@@ -176,7 +439,48 @@ func (i *Interpreter) visitBlockStmt(statement *BlockStmt) interface{} {
 }
 
 func (i *Interpreter) visitBreakStmt(statement *BreakStmt) interface{} {
-	panic(LoopAction{"BREAK"})
+	panic(NewLoopAction(BREAKACTION, statement.Label))
+}
+
+func (i *Interpreter) visitClassStmt(statement *ClassStmt) interface{} {
+	var superclass *SymClass
+	if statement.Superclass != nil {
+		value := i.evaluate(statement.Superclass)
+		class, ok := value.(*SymClass)
+		if !ok {
+			panic(NewRuntimeError(TYPEERROR, statement.Superclass.Name.Line, fmt.Sprintf("Superclass must be a class, got %v.", value)))
+		}
+		superclass = class
+	}
+	i.declare(statement.Name, nil)
+	closure := i.environment
+	if superclass != nil {
+		closure = NewEnvironmentWithEnclosing(i.environment)
+		closure.define(SUPER, superclass)
+	}
+	methods := make(map[string]*SymFunction)
+	for _, method := range statement.Methods {
+		isInitializer := method.Name.Lexeme == "init"
+		methods[method.Name.Lexeme] = NewSymFunction(method, closure, isInitializer)
+	}
+	class := NewSymClass(statement.Name.Lexeme, superclass, statement.Fields, methods, closure)
+	i.environment.assign(statement.Name.Lexeme, class)
+	return nil
+}
+
+func (i *Interpreter) visitContinueStmt(statement *ContinueStmt) interface{} {
+	panic(NewLoopAction(CONTINUEACTION, statement.Label))
+}
+
+func (i *Interpreter) visitEnumStmt(statement *EnumStmt) interface{} {
+	i.declare(statement.Name, NewSymEnum(statement))
+	return nil
+}
+
+func (i *Interpreter) visitExportStmt(statement *ExportStmt) interface{} {
+	i.execute(statement.Declaration)
+	i.module.exports[statement.Name.Lexeme] = true
+	return nil
 }
 
 func (i *Interpreter) visitExpressionStmt(statement *ExpressionStmt) interface{} {
@@ -186,8 +490,8 @@ func (i *Interpreter) visitExpressionStmt(statement *ExpressionStmt) interface{}
 }
 
 func (i *Interpreter) visitFunctionStmt(statement *FunctionStmt) interface{} {
-	function := NewSymFunction(statement, i.environment)
-	i.environment.define(statement.Name.Lexeme, function)
+	function := NewSymFunction(statement, i.environment, false)
+	i.declare(statement.Name, function)
 	return nil
 }
 
@@ -195,13 +499,73 @@ func (i *Interpreter) visitIfStmt(statement *IfStmt) interface{} {
 	condition := i.evaluate(statement.Condition)
 	if i.isTruthy(condition) {
 		return i.execute(statement.Then)
+	} else if statement.Else != nil {
+		return i.execute(statement.Else)
 	}
 	return nil
 }
 
+func (i *Interpreter) visitImportStmt(statement *ImportStmt) interface{} {
+	module := i.importModule(statement.Path)
+	i.declare(statement.Alias, module)
+	return nil
+}
+
 func (i *Interpreter) visitLoopStmt(statement *LoopStmt) interface{} {
+	if statement.Initializer != nil {
+		previous := i.environment
+		defer func() {
+			i.environment = previous
+		}()
+		var iterator SymIterator
+		if statement.Iterable != nil {
+			variable := statement.Initializer.(*VarStmt).Name
+			iterator = i.iterator(variable, i.evaluate(statement.Iterable))
+		}
+		i.environment = NewEnvironmentWithEnclosing(previous)
+		i.execute(statement.Initializer)
+		if iterator != nil {
+			return i.loopIterator(statement, iterator)
+		}
+	}
 	for {
-		if i.loop(statement.Body) == "BREAK" {
+		if statement.Condition != nil && !i.isTruthy(i.evaluate(statement.Condition)) {
+			break
+		}
+		if i.loop(statement) == BREAKACTION {
+			break
+		}
+		if statement.Initializer != nil {
+			i.nextIteration(statement)
+		}
+		if statement.Increment != nil {
+			i.evaluate(statement.Increment)
+		}
+	}
+	return nil
+}
+
+// nextIteration gives each pass a new loop variable for closures to capture.
+func (i *Interpreter) nextIteration(statement *LoopStmt) {
+	name := statement.Initializer.(*VarStmt).Name.Lexeme
+	value, _ := i.environment.get(name)
+	i.environment = NewEnvironmentWithEnclosing(i.environment.enclosing)
+	i.environment.define(name, value)
+}
+
+func (i *Interpreter) loopIterator(statement *LoopStmt, iterator SymIterator) interface{} {
+	name := statement.Initializer.(*VarStmt).Name.Lexeme
+	if generator, ok := iterator.(*SymGenerator); ok {
+		defer generator.Close()
+	}
+	for {
+		value, ok := iterator.Next()
+		if !ok {
+			break
+		}
+		i.environment = NewEnvironmentWithEnclosing(i.environment.enclosing)
+		i.environment.define(name, value)
+		if i.loop(statement) == BREAKACTION {
 			break
 		}
 	}
@@ -210,77 +574,424 @@ func (i *Interpreter) visitLoopStmt(statement *LoopStmt) interface{} {
 
 type ActionType = string
 
+const (
+	BREAKACTION    ActionType = "BREAK"
+	CONTINUEACTION ActionType = "CONTINUE"
+)
+
 type LoopAction struct {
 	actionType ActionType
+	label      string
 }
 
-func (i *Interpreter) loop(body Stmt) (actionType ActionType) {
+func NewLoopAction(actionType ActionType, label *Token) LoopAction {
+	action := LoopAction{actionType: actionType}
+	if label != nil {
+		action.label = label.Lexeme
+	}
+	return action
+}
+
+// loop runs one pass of the body. Labeled actions for other loops keep unwinding.
+func (i *Interpreter) loop(statement *LoopStmt) (actionType ActionType) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch r := r.(type) {
 			case LoopAction:
+				if r.label != "" && (statement.Label == nil || statement.Label.Lexeme != r.label) {
+					panic(r)
+				}
 				actionType = r.actionType
-				break
 			default:
 				panic(r)
 			}
 		}
 	}()
-	i.execute(body)
+	i.execute(statement.Body)
 	return ""
 }
 
+func (i *Interpreter) visitMatchStmt(statement *MatchStmt) interface{} {
+	subject := i.evaluate(statement.Subject)
+	arm, environment := i.matchArm(statement.Arms, subject)
+	if arm != nil {
+		i.executeBlock([]Stmt{arm.Body}, environment)
+	}
+	return nil
+}
+
 func (i *Interpreter) visitPrintStmt(statement *PrintStmt) interface{} {
 	value := i.evaluate(statement.Expression)
-	fmt.Printf("%v\n", value)
+	fmt.Println(i.stringify(value))
 	i.currentValue = value
 	return value
 }
 
+func (i *Interpreter) visitRecordStmt(statement *RecordStmt) interface{} {
+	i.declare(statement.Name, NewSymRecordType(statement))
+	return nil
+}
+
 func (i *Interpreter) visitReturnStmt(statement *ReturnStmt) interface{} {
-	value := i.evaluate(statement.Value)
+	var value interface{}
+	if statement.Value != nil && i.resolution.isTail(statement) {
+		value = i.tailValue(statement.Value)
+	} else if statement.Value != nil {
+		value = i.evaluate(statement.Value)
+	}
 	panic(NewSymReturn(value))
 }
 
+// tailValue treats both branches of a conditional as tail positions too.
+func (i *Interpreter) tailValue(expression Expr) interface{} {
+	switch expression := expression.(type) {
+	case *CallExpr:
+		return i.tailCall(expression)
+	case *ConditionalExpr:
+		if i.isTruthy(i.evaluate(expression.Condition)) {
+			return i.tailValue(expression.Then)
+		}
+		return i.tailValue(expression.Else)
+	default:
+		return i.evaluate(expression)
+	}
+}
+
+func (i *Interpreter) visitThrowStmt(statement *ThrowStmt) interface{} {
+	value := i.evaluate(statement.Value)
+	panic(NewSymThrow(value, statement.Keyword.Line))
+}
+
+func (i *Interpreter) visitTryStmt(statement *TryStmt) interface{} {
+	if statement.Finally != nil {
+		defer i.execute(statement.Finally)
+	}
+	if statement.Catch == nil {
+		i.execute(statement.Body)
+		return nil
+	}
+	caught, ok := i.try(statement.Body)
+	if ok {
+		environment := NewEnvironmentWithEnclosing(i.environment)
+		if statement.CatchName != nil {
+			environment.define(statement.CatchName.Lexeme, caught)
+		}
+		i.executeBlock(statement.Catch.Statements, environment)
+	}
+	return nil
+}
+
+// try catches '↯' values and runtime errors. Anything else keeps unwinding.
+func (i *Interpreter) try(body Stmt) (caught interface{}, ok bool) {
+	defer func() {
+		r := recover()
+		switch r := r.(type) {
+		case nil:
+		case *SymThrow:
+			caught, ok = r.Value, true
+		case *RuntimeError:
+			caught, ok = r, true
+		default:
+			panic(r)
+		}
+	}()
+	i.execute(body)
+	return nil, false
+}
+
 func (i *Interpreter) visitVarStmt(statement *VarStmt) interface{} {
 	var value interface{}
 	if statement.Initializer != nil {
 		value = i.evaluate(statement.Initializer)
 	}
-	i.environment.define(statement.Name.Lexeme, value)
+	if statement.Constant {
+		i.checkRedeclaration(statement.Name)
+		i.environment.defineConstant(statement.Name.Lexeme, value)
+	} else {
+		i.declare(statement.Name, value)
+	}
 	return value
 }
 
+func (i *Interpreter) declare(name Token, value interface{}) {
+	i.checkRedeclaration(name)
+	i.environment.define(name.Lexeme, value)
+}
+
+// checkRedeclaration catches global constants, which the resolver can't see.
+func (i *Interpreter) checkRedeclaration(name Token) {
+	if i.environment.isConstant(name.Lexeme) {
+		panic(NewRuntimeError(CONSTANTERROR, name.Line, fmt.Sprintf("Can't redeclare constant '%s'.", name.Lexeme)))
+	}
+}
+
+func (i *Interpreter) add(operator Token, left interface{}, right interface{}) interface{} {
+	leftValue, leftOk := left.(string)
+	rightValue, rightOk := right.(string)
+	if leftOk && rightOk {
+		return leftValue + rightValue
+	}
+	if !isNumber(left) || !isNumber(right) {
+		panic(NewRuntimeError(TYPEERROR, operator.Line,
+			fmt.Sprintf("Operands must be two numbers or two strings, got %v and %v.", left, right)))
+	}
+	return i.arithmetic(operator, left, right)
+}
+
+func (i *Interpreter) getProperty(object interface{}, name Token) interface{} {
+	switch object := object.(type) {
+	case *SymInstance:
+		return object.get(name)
+	case *RuntimeError:
+		return object.get(name)
+	case *SymEnum:
+		return object.get(name)
+	case *SymVariant:
+		return object.get(name)
+	case *SymRecord:
+		return object.get(name)
+	case *SymModule:
+		return object.get(name)
+	default:
+		panic(NewRuntimeError(TYPEERROR, name.Line, fmt.Sprintf("Only instances have properties, got %v.", object)))
+	}
+}
+
+func (i *Interpreter) setProperty(object interface{}, name Token, value interface{}) {
+	_, ok := object.(*SymVariant)
+	if ok {
+		panic(NewRuntimeError(TYPEERROR, name.Line, fmt.Sprintf("Can't assign to field '%s' of %v, enum variants are immutable.", name.Lexeme, object)))
+	}
+	record, ok := object.(*SymRecord)
+	if ok {
+		record.set(name, value)
+		return
+	}
+	instance, ok := object.(*SymInstance)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, name.Line, fmt.Sprintf("Only instances have fields, got %v.", object)))
+	}
+	instance.set(name, value)
+}
+
+func (i *Interpreter) getIndex(object interface{}, index interface{}, bracket Token) interface{} {
+	switch object := object.(type) {
+	case *SymList:
+		return object.get(i.listIndex(index))
+	case *SymMap:
+		return object.get(index)
+	default:
+		panic(NewRuntimeError(TYPEERROR, bracket.Line, fmt.Sprintf("Can only index lists and maps, got %v.", object)))
+	}
+}
+
+func (i *Interpreter) setIndex(object interface{}, index interface{}, value interface{}, bracket Token) {
+	switch object := object.(type) {
+	case *SymList:
+		object.set(i.listIndex(index), value)
+	case *SymMap:
+		object.set(index, value)
+	default:
+		panic(NewRuntimeError(TYPEERROR, bracket.Line, fmt.Sprintf("Can only index lists and maps, got %v.", object)))
+	}
+}
+
 func (i *Interpreter) isEqual(left interface{}, right interface{}) bool {
+	return i.equal(left, right, nil)
+}
+
+// equal treats a pair of collections that it is already comparing as equal,
+// so that collections holding themselves can be compared.
+func (i *Interpreter) equal(left interface{}, right interface{}, comparing map[[2]interface{}]bool) bool {
 	if left == nil && right == nil {
 		return true
 	}
 	if left == nil {
 		return false
 	}
+	if isNumber(left) && isNumber(right) {
+		return numbersEqual(left, right)
+	}
+	switch left.(type) {
+	case *SymList, *SymMap, *SymRecord, *SymVariant:
+		pair := [2]interface{}{left, right}
+		if comparing[pair] {
+			return true
+		}
+		if comparing == nil {
+			comparing = make(map[[2]interface{}]bool)
+		}
+		comparing[pair] = true
+		defer delete(comparing, pair)
+	}
+	leftList, leftOk := left.(*SymList)
+	rightList, rightOk := right.(*SymList)
+	if leftOk && rightOk {
+		leftElements, rightElements := leftList.elements(), rightList.elements()
+		if len(leftElements) != len(rightElements) {
+			return false
+		}
+		for index := range leftElements {
+			if !i.equal(leftElements[index], rightElements[index], comparing) {
+				return false
+			}
+		}
+		return true
+	}
+	leftVariant, leftOk := left.(*SymVariant)
+	rightVariant, rightOk := right.(*SymVariant)
+	if leftOk && rightOk {
+		if leftVariant.Constructor != rightVariant.Constructor {
+			return false
+		}
+		for index := range leftVariant.Values {
+			if !i.equal(leftVariant.Values[index], rightVariant.Values[index], comparing) {
+				return false
+			}
+		}
+		return true
+	}
+	leftRecord, leftOk := left.(*SymRecord)
+	rightRecord, rightOk := right.(*SymRecord)
+	if leftOk && rightOk {
+		if leftRecord.Type != rightRecord.Type {
+			return false
+		}
+		leftValues, rightValues := leftRecord.values(), rightRecord.values()
+		for index := range leftValues {
+			if !i.equal(leftValues[index], rightValues[index], comparing) {
+				return false
+			}
+		}
+		return true
+	}
+	leftMap, leftOk := left.(*SymMap)
+	rightMap, rightOk := right.(*SymMap)
+	if leftOk && rightOk {
+		leftEntries, rightEntries := leftMap.entries(), rightMap.entries()
+		if len(leftEntries) != len(rightEntries) {
+			return false
+		}
+		for key, leftValue := range leftEntries {
+			rightValue, ok := rightEntries[key]
+			if !ok || !i.equal(leftValue, rightValue, comparing) {
+				return false
+			}
+		}
+		return true
+	}
 	return left == right
 }
 
+func (i *Interpreter) contains(operator Token, container interface{}, element interface{}) bool {
+	switch container := container.(type) {
+	case *SymMap:
+		return container.has(element)
+	case *SymList:
+		for _, candidate := range container.elements() {
+			if i.isEqual(candidate, element) {
+				return true
+			}
+		}
+		return false
+	case string:
+		text, ok := element.(string)
+		if !ok {
+			panic(NewRuntimeError(TYPEERROR, operator.Line, fmt.Sprintf("Can only look for a string in a string, got %v.", element)))
+		}
+		return strings.Contains(container, text)
+	default:
+		panic(NewRuntimeError(TYPEERROR, operator.Line,
+			fmt.Sprintf("Can only test membership in maps, lists and strings, got %v.", container)))
+	}
+}
+
+func (i *Interpreter) iterator(token Token, object interface{}) SymIterator {
+	switch object := object.(type) {
+	case *SymList:
+		return object.iterator()
+	case *SymMap:
+		return object.iterator()
+	case string:
+		return &stringIterator{runes: []rune(object)}
+	case *SymGenerator:
+		return object
+	case *SymChannel:
+		return &channelIterator{interpreter: i, channel: object}
+	default:
+		panic(NewRuntimeError(TYPEERROR, token.Line, fmt.Sprintf("Can only iterate over lists, maps, strings, generators and channels, got %v.", object)))
+	}
+}
+
+func (i *Interpreter) listIndex(index interface{}) int {
+	value, ok := index.(int64)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("List index must be an integer, got %v.", index)))
+	}
+	return int(value)
+}
+
 func (i *Interpreter) isTruthy(object interface{}) bool {
 	if object == nil {
 		return false
 	}
-	isBool, ok := object.(bool)
-	if ok {
-		return isBool
+	switch object := object.(type) {
+	case bool:
+		return object
+	case *SymList:
+		return object.length() > 0
+	case *SymMap:
+		return object.length() > 0
 	}
 	return true
 }
 
+// atLine gives errors raised by natives and collections the line of the call.
+func (i *Interpreter) atLine(token Token) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	runtimeError, ok := r.(*RuntimeError)
+	if ok && runtimeError.Line == 0 {
+		runtimeError.Line = token.Line
+	}
+	panic(r)
+}
+
+func (i *Interpreter) stringify(value interface{}) string {
+	return fmt.Sprintf("%v", value)
+}
+
+func (i *Interpreter) assignVariable(name Token, expression Expr, value interface{}) {
+	distance, ok := i.resolution.local(expression)
+	if ok {
+		i.environment.assignAt(distance, name.Lexeme, value)
+	} else {
+		// The resolver can't see global constants.
+		globals := i.globals()
+		if globals.isConstant(name.Lexeme) {
+			panic(NewRuntimeError(CONSTANTERROR, name.Line, fmt.Sprintf("Can't assign to constant '%s'.", name.Lexeme)))
+		}
+		globals.assign(name.Lexeme, value)
+	}
+}
+
 func (i *Interpreter) variableLookup(name Token, expression Expr) interface{} {
-	distance, ok := i.locals[expression]
+	distance, ok := i.resolution.local(expression)
 	if ok {
 		return i.environment.getAt(distance, name.Lexeme)
 	} else {
-		value, ok := i.globals.get(name.Lexeme)
+		value, ok := i.globals().get(name.Lexeme)
 		if !ok {
-			panic(fmt.Sprintf("Undefined variable '%s'.", name.Lexeme))
+			panic(NewRuntimeError(NAMEERROR, name.Line, fmt.Sprintf("Undefined variable '%s'.", name.Lexeme)))
 		}
 		return value
 	}
 }
+
+func (i *Interpreter) visitYieldStmt(statement *YieldStmt) interface{} {
+	value := i.evaluate(statement.Value)
+	i.generator.yield(value)
+	return nil
+}
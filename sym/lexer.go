@@ -2,28 +2,49 @@ package sym
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 var keywords = map[string]string{
 	"&": AND,
 	"←": ASSIGN,
 	"Ɵ": BREAK,
+	"⚐": CATCH,
+	"©": CLASS,
+	"≡": CONST,
+	"↻": CONTINUE,
+	"¡": ELSE,
+	"∑": ENUM,
+	"⇑": EXPORT,
 	"○": FALSE,
+	"∎": FINALLY,
 	"ƒ": FUNC,
 	"¿": IF,
+	"⇓": IMPORT,
 	"∞": LOOP,
+	"⋔": MATCH,
 	"ø": NIL,
 	"|": OR,
 	"✉": PRINT,
+	"®": RECORD,
+	"⇶": SPAWN,
 	"↵": RETURN,
+	"↑": SUPER,
+	"þ": THIS,
+	"↯": THROW,
 	"●": TRUE,
+	"☂": TRY,
 	"•": VAR,
+	"↳": YIELD,
 }
 
 type Lexer struct {
 	source  []rune
 	tokens  []Token
+	trivia  []Trivia
 	start   int
 	current int
 	line    int
@@ -33,6 +54,7 @@ func NewLexer(source string) Lexer {
 	return Lexer{
 		source:  []rune(source),
 		tokens:  []Token{},
+		trivia:  []Trivia{},
 		start:   0,
 		current: 0,
 		line:    1,
@@ -40,11 +62,13 @@ func NewLexer(source string) Lexer {
 }
 
 func (l *Lexer) scanTokens() []Token {
+	l.shebang()
 	for !l.isAtEnd() {
 		l.start = l.current
 		l.scanToken()
 	}
-	l.tokens = append(l.tokens, NewToken(EOF, "", nil, l.line))
+	l.start = l.current
+	l.addToken(EOF)
 	return l.tokens
 }
 
@@ -59,20 +83,54 @@ func (l *Lexer) scanToken() {
 		l.addToken(LEFTBRACE)
 	case '}':
 		l.addToken(RIGHTBRACE)
+	case '[':
+		l.addToken(LEFTBRACKET)
+	case ']':
+		l.addToken(RIGHTBRACKET)
+	case '→':
+		l.addToken(ARROW)
 	case ',':
 		l.addToken(COMMA)
 	case '.':
 		l.addToken(DOT)
+	case '…':
+		l.addToken(ELLIPSIS)
 	case ';':
 		l.addToken(SEMICOLON)
+	case '/':
+		if l.match('/') {
+			l.lineComment()
+		} else if l.match('*') {
+			l.blockComment()
+		} else {
+			fmt.Println(fmt.Errorf("Unexpected character %s at line %d.", string(c), l.line))
+		}
 	case '-':
-		l.addToken(MINUS)
+		if l.match('-') {
+			l.addToken(DECREMENT)
+		} else {
+			l.addOperator(MINUS, MINUSASSIGN)
+		}
 	case '+':
-		l.addToken(PLUS)
+		if l.match('+') {
+			l.addToken(INCREMENT)
+		} else {
+			l.addOperator(PLUS, PLUSASSIGN)
+		}
 	case '÷':
-		l.addToken(DIVIDE)
+		l.addOperator(DIVIDE, DIVIDEASSIGN)
 	case '×':
-		l.addToken(MULTIPLY)
+		l.addOperator(MULTIPLY, MULTIPLYASSIGN)
+	case '%':
+		l.addOperator(MODULO, MODULOASSIGN)
+	case '^':
+		l.addOperator(POWER, POWERASSIGN)
+	case '⌊':
+		if l.match('÷') {
+			l.addOperator(FLOORDIVIDE, FLOORDIVIDEASSIGN)
+		} else {
+			fmt.Println(fmt.Errorf("Unexpected character %s at line %d.", string(c), l.line))
+		}
 	case '!':
 		l.addToken(BANG)
 	case '=':
@@ -87,12 +145,24 @@ func (l *Lexer) scanToken() {
 		l.addToken(LESS)
 	case '≤':
 		l.addToken(LESSEQUAL)
+	case '∈':
+		l.addToken(IN)
 	case ' ', '\r', '\t':
 		break
 	case '\n':
 		l.line++
 	case '"':
-		l.string()
+		if l.peek() == '"' && l.peekNext() == '"' {
+			l.advance()
+			l.advance()
+			l.multilineString()
+		} else {
+			l.string()
+		}
+	case '`':
+		l.rawString()
+	case '@':
+		l.label()
 	default:
 		if l.isDigit(c) {
 			l.number()
@@ -108,12 +178,34 @@ func (l *Lexer) addToken(tokenType string) {
 	l.addTokenLiteral(tokenType, nil)
 }
 
+func (l *Lexer) addOperator(operator string, assignment string) {
+	if l.match('←') {
+		l.addToken(assignment)
+	} else {
+		l.addToken(operator)
+	}
+}
+
 func (l *Lexer) addTokenLiteral(tokenType string, literal interface{}) {
 	text := string(l.source[l.start:l.current])
 	token := NewToken(tokenType, text, literal, l.line)
+	token.Leading = l.trivia
+	l.trivia = []Trivia{}
 	l.tokens = append(l.tokens, token)
 }
 
+// addTrivia attaches a comment to the token on its line, or else the next one.
+func (l *Lexer) addTrivia(triviaType string, line int) {
+	text := string(l.source[l.start:l.current])
+	trivia := NewTrivia(triviaType, text, line)
+	if len(l.tokens) > 0 && l.tokens[len(l.tokens)-1].Line == line {
+		previous := &l.tokens[len(l.tokens)-1]
+		previous.Trailing = append(previous.Trailing, trivia)
+		return
+	}
+	l.trivia = append(l.trivia, trivia)
+}
+
 func (l *Lexer) advance() rune {
 	c := l.source[l.current]
 	l.current++
@@ -127,16 +219,54 @@ func (l *Lexer) isAlpha(c rune) bool {
 		c == '&' ||
 		c == '←' ||
 		c == 'Ɵ' ||
+		c == '⚐' ||
+		c == '©' ||
+		c == '≡' ||
+		c == '↻' ||
+		c == '¡' ||
+		c == '∑' ||
+		c == '⇑' ||
 		c == '○' ||
+		c == '∎' ||
 		c == 'ƒ' ||
 		c == '¿' ||
+		c == '⇓' ||
 		c == '∞' ||
+		c == '⋔' ||
 		c == 'ø' ||
 		c == '|' ||
 		c == '✉' ||
+		c == '®' ||
+		c == '⇶' ||
 		c == '↵' ||
+		c == '↑' ||
+		c == 'þ' ||
+		c == '↯' ||
 		c == '●' ||
-		c == '•'
+		c == '☂' ||
+		c == '•' ||
+		c == '↳'
+}
+
+func (l *Lexer) blockComment() {
+	line := l.line
+	depth := 1
+	for depth > 0 && !l.isAtEnd() {
+		if l.peek() == '/' && l.peekNext() == '*' {
+			l.advance()
+			depth++
+		} else if l.peek() == '*' && l.peekNext() == '/' {
+			l.advance()
+			depth--
+		} else if l.peek() == '\n' {
+			l.line++
+		}
+		l.advance()
+	}
+	if depth > 0 {
+		fmt.Println(fmt.Errorf("Unterminated block comment at line %d.", line))
+	}
+	l.addTrivia(BLOCKCOMMENT, line)
 }
 
 func (l *Lexer) identifier() {
@@ -164,6 +294,23 @@ func (l *Lexer) isDigit(c rune) bool {
 	return c >= '0' && c <= '9'
 }
 
+func (l *Lexer) label() {
+	if !l.isAlpha(l.peek()) {
+		fmt.Println(fmt.Errorf("Expect label name after '@' at line %d.", l.line))
+	}
+	for l.isAlphaNumeric(l.peek()) {
+		l.advance()
+	}
+	l.addTokenLiteral(LABEL, string(l.source[l.start:l.current]))
+}
+
+func (l *Lexer) lineComment() {
+	for l.peek() != '\n' && !l.isAtEnd() {
+		l.advance()
+	}
+	l.addTrivia(LINECOMMENT, l.line)
+}
+
 func (l *Lexer) match(expected rune) bool {
 	if l.isAtEnd() {
 		return false
@@ -184,12 +331,18 @@ func (l *Lexer) number() {
 		for l.isDigit(l.peek()) {
 			l.advance()
 		}
+		number, err := strconv.ParseFloat(string(l.source[l.start:l.current]), 64)
+		if err != nil {
+			fmt.Println(fmt.Errorf("%s at line %d.", err.Error(), l.line))
+		}
+		l.addTokenLiteral(NUMBER, number)
+		return
 	}
-	number, err := strconv.ParseFloat(string(l.source[l.start:l.current]), 64)
-	if err != nil {
-		fmt.Println(fmt.Errorf("%s at line %d.", err.Error(), l.line))
+	number, ok := new(big.Int).SetString(string(l.source[l.start:l.current]), 10)
+	if !ok {
+		fmt.Println(fmt.Errorf("Invalid integer %s at line %d.", string(l.source[l.start:l.current]), l.line))
 	}
-	l.addTokenLiteral(NUMBER, number)
+	l.addTokenLiteral(NUMBER, normalizeInt(number))
 }
 
 func (l *Lexer) peek() rune {
@@ -203,20 +356,218 @@ func (l *Lexer) peekNext() rune {
 	if l.current+1 >= len(l.source) {
 		return '\000'
 	}
-	return l.source[l.current]
+	return l.source[l.current+1]
+}
+
+func (l *Lexer) peekAt(offset int) rune {
+	if l.current+offset >= len(l.source) {
+		return '\000'
+	}
+	return l.source[l.current+offset]
+}
+
+func (l *Lexer) shebang() {
+	if l.peek() != '#' || l.peekNext() != '!' {
+		return
+	}
+	for l.peek() != '\n' && !l.isAtEnd() {
+		l.advance()
+	}
+	l.addTrivia(SHEBANG, l.line)
 }
 
 func (l *Lexer) string() {
-	for l.peek() != '"' && !l.isAtEnd() {
+	parts, text := l.stringContents(func() bool {
+		return l.peek() == '"'
+	})
+	if l.isAtEnd() {
+		fmt.Println(fmt.Errorf("Unterminated string at line %d.", l.line))
+		return
+	}
+	l.advance()
+	l.addStringToken(parts, text)
+}
+
+// multilineString reads a '"""' string and removes its shared indentation.
+func (l *Lexer) multilineString() {
+	line := l.line
+	start := l.current
+	for !l.isAtEnd() && !(l.peek() == '"' && l.peekNext() == '"' && l.peekAt(2) == '"') {
+		if l.peek() == '\\' {
+			l.advance()
+		}
 		if l.peek() == '\n' {
 			l.line++
 		}
 		l.advance()
 	}
 	if l.isAtEnd() {
-		fmt.Println(fmt.Errorf("Unterminated string at line %d.", l.line))
+		fmt.Println(fmt.Errorf("Unterminated multi-line string at line %d.", line))
+		return
 	}
+	content := string(l.source[start:l.current])
+	l.advance()
 	l.advance()
-	value := string(l.source[l.start+1 : l.current-1])
-	l.addTokenLiteral(STRING, value)
+	l.advance()
+	if strings.HasPrefix(content, "\n") {
+		content = content[1:]
+		line++
+	} else if strings.HasPrefix(content, "\r\n") {
+		content = content[2:]
+		line++
+	}
+	contents := NewLexer(dedent(content))
+	contents.line = line
+	parts, text := contents.stringContents(func() bool {
+		return false
+	})
+	l.addStringToken(parts, text)
+}
+
+func (l *Lexer) rawString() {
+	for l.peek() != '`' && !l.isAtEnd() {
+		if l.peek() == '\n' {
+			l.line++
+		}
+		l.advance()
+	}
+	if l.isAtEnd() {
+		fmt.Println(fmt.Errorf("Unterminated raw string at line %d.", l.line))
+		return
+	}
+	l.advance()
+	l.addTokenLiteral(STRING, string(l.source[l.start+1:l.current-1]))
+}
+
+func (l *Lexer) stringContents(isEnd func() bool) ([]StringPart, string) {
+	var parts []StringPart
+	var text strings.Builder
+	for !isEnd() && !l.isAtEnd() {
+		c := l.advance()
+		if c == '\n' {
+			l.line++
+		}
+		if c != '\\' {
+			text.WriteRune(c)
+			continue
+		}
+		if l.match('(') {
+			parts = append(parts, NewStringPart(text.String(), false, l.line))
+			text.Reset()
+			parts = append(parts, l.interpolation())
+			continue
+		}
+		text.WriteString(l.escape())
+	}
+	return parts, text.String()
+}
+
+func (l *Lexer) addStringToken(parts []StringPart, text string) {
+	if parts == nil {
+		l.addTokenLiteral(STRING, text)
+		return
+	}
+	parts = append(parts, NewStringPart(text, false, l.line))
+	l.addTokenLiteral(TEMPLATE, parts)
+}
+
+func (l *Lexer) escape() string {
+	if l.isAtEnd() {
+		return ""
+	}
+	c := l.advance()
+	switch c {
+	case 'n':
+		return "\n"
+	case 't':
+		return "\t"
+	case 'r':
+		return "\r"
+	case '"', '\\':
+		return string(c)
+	case 'u':
+		if !l.match('{') {
+			fmt.Println(fmt.Errorf("Expect '{' after '\\u' at line %d.", l.line))
+			return ""
+		}
+		start := l.current
+		for l.peek() != '}' && l.peek() != '"' && !l.isAtEnd() {
+			l.advance()
+		}
+		digits := string(l.source[start:l.current])
+		if !l.match('}') {
+			fmt.Println(fmt.Errorf("Expect '}' after unicode escape at line %d.", l.line))
+			return ""
+		}
+		code, err := strconv.ParseUint(digits, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			fmt.Println(fmt.Errorf("Invalid unicode escape '\\u{%s}' at line %d.", digits, l.line))
+			return ""
+		}
+		return string(rune(code))
+	default:
+		fmt.Println(fmt.Errorf("Unknown escape sequence '\\%s' at line %d.", string(c), l.line))
+		return string(c)
+	}
+}
+
+// interpolation reads the source of a '\(' expression up to its matching ')'.
+func (l *Lexer) interpolation() StringPart {
+	line := l.line
+	start := l.current
+	depth := 1
+	for !l.isAtEnd() {
+		c := l.peek()
+		if c == ')' {
+			depth--
+			if depth == 0 {
+				break
+			}
+		} else if c == '(' {
+			depth++
+		} else if c == '\n' {
+			l.line++
+		} else if c == '"' {
+			l.advance()
+			for l.peek() != '"' && !l.isAtEnd() {
+				if l.peek() == '\n' {
+					l.line++
+				} else if l.peek() == '\\' {
+					l.advance()
+				}
+				l.advance()
+			}
+		}
+		l.advance()
+	}
+	source := string(l.source[start:l.current])
+	if !l.match(')') {
+		fmt.Println(fmt.Errorf("Unterminated interpolation at line %d.", line))
+	}
+	return NewStringPart(source, true, line)
+}
+
+func dedent(content string) string {
+	lines := strings.Split(content, "\n")
+	if strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	indentation := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indentation == -1 || width < indentation {
+			indentation = width
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indentation && indentation > 0 {
+			lines[i] = line[indentation:]
+		} else if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
 }
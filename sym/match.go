@@ -0,0 +1,110 @@
+package sym
+
+import "fmt"
+
+// matchArm returns the first matching arm and the environment of its bindings.
+func (i *Interpreter) matchArm(arms []*MatchArm, subject interface{}) (*MatchArm, *Environment) {
+	previous := i.environment
+	defer func() {
+		i.environment = previous
+	}()
+	for _, arm := range arms {
+		i.environment = NewEnvironmentWithEnclosing(previous)
+		if i.matches(arm.Pattern, subject) && (arm.Guard == nil || i.isTruthy(i.evaluate(arm.Guard))) {
+			return arm, i.environment
+		}
+	}
+	return nil, nil
+}
+
+// matches binds names as it goes, so a failed match can leave some behind.
+func (i *Interpreter) matches(pattern Pattern, value interface{}) bool {
+	switch pattern := pattern.(type) {
+	case *WildcardPattern:
+		return true
+	case *BindingPattern:
+		i.environment.define(pattern.Name.Lexeme, value)
+		return true
+	case *ValuePattern:
+		return i.isEqual(i.evaluate(pattern.Value), value)
+	case *ConstructorPattern:
+		return i.matchesConstructor(pattern, value)
+	case *ListPattern:
+		return i.matchesList(pattern, value)
+	case *MapPattern:
+		return i.matchesMap(pattern, value)
+	}
+	panic("You done messed up.")
+}
+
+func (i *Interpreter) matchesConstructor(pattern *ConstructorPattern, value interface{}) bool {
+	callee := i.evaluate(pattern.Constructor)
+	var fields []interface{}
+	switch constructor := callee.(type) {
+	case *VariantConstructor:
+		variant, ok := value.(*SymVariant)
+		if ok && variant.Constructor == constructor {
+			fields = variant.Values
+		}
+	case *SymRecordType:
+		record, ok := value.(*SymRecord)
+		if ok && record.Type == constructor {
+			fields = record.values()
+		}
+	default:
+		panic(NewRuntimeError(TYPEERROR, pattern.Parenthesis.Line, fmt.Sprintf("Can only destructure enum variants and records, got %v.", callee)))
+	}
+	function := callee.(SymCallable)
+	if len(pattern.Fields) != len(function.Parameters()) {
+		panic(NewRuntimeError(ARGUMENTERROR, pattern.Parenthesis.Line,
+			fmt.Sprintf("%s takes %v but the pattern has %d.", function.Signature(), function.Arity(), len(pattern.Fields))))
+	}
+	if fields == nil {
+		return false
+	}
+	for index, field := range pattern.Fields {
+		if !i.matches(field, fields[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *Interpreter) matchesList(pattern *ListPattern, value interface{}) bool {
+	list, ok := value.(*SymList)
+	if !ok {
+		return false
+	}
+	elements := list.elements()
+	count := len(pattern.Elements)
+	if len(elements) < count || (pattern.Rest == nil && len(elements) != count) {
+		return false
+	}
+	for index, element := range pattern.Elements {
+		if !i.matches(element, elements[index]) {
+			return false
+		}
+	}
+	if pattern.Rest != nil && pattern.Rest.Lexeme != "_" {
+		rest := elements[count:]
+		i.environment.define(pattern.Rest.Lexeme, NewSymList(rest))
+	}
+	return true
+}
+
+func (i *Interpreter) matchesMap(pattern *MapPattern, value interface{}) bool {
+	symMap, ok := value.(*SymMap)
+	if !ok {
+		return false
+	}
+	if len(pattern.Keys) == 0 {
+		return symMap.length() == 0
+	}
+	for index := range pattern.Keys {
+		key := i.evaluate(pattern.Keys[index])
+		if !symMap.has(key) || !i.matches(pattern.Values[index], symMap.get(key)) {
+			return false
+		}
+	}
+	return true
+}
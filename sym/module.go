@@ -0,0 +1,116 @@
+package sym
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const MODULEEXTENSION = ".sym"
+
+// SymModule exposes only the names exported with '⇑', or all of them if none are.
+type SymModule struct {
+	Name        string
+	Path        string
+	environment *Environment
+	exports     map[string]bool
+}
+
+func NewSymModule(name string, path string, environment *Environment) *SymModule {
+	return &SymModule{
+		Name:        name,
+		Path:        path,
+		environment: environment,
+		exports:     make(map[string]bool),
+	}
+}
+
+func (sm *SymModule) get(name Token) interface{} {
+	if len(sm.exports) > 0 && !sm.exports[name.Lexeme] {
+		panic(NewRuntimeError(PROPERTYERROR, name.Line, fmt.Sprintf("Module '%s' doesn't export '%s'.", sm.Name, name.Lexeme)))
+	}
+	value, ok := sm.environment.get(name.Lexeme)
+	_, native := value.(*NativeFunction)
+	if !ok || native {
+		panic(NewRuntimeError(PROPERTYERROR, name.Line, fmt.Sprintf("Module '%s' has no binding '%s'.", sm.Name, name.Lexeme)))
+	}
+	return value
+}
+
+func (sm *SymModule) String() string {
+	return fmt.Sprintf("<⇓ %s>", sm.Name)
+}
+
+func (i *Interpreter) importModule(path Token) *SymModule {
+	file := i.findModule(path)
+	module, ok := i.modules[file]
+	if ok {
+		return module
+	}
+	for index, importing := range i.importing {
+		if importing == file {
+			cycle := append(append([]string{}, i.importing[index:]...), file)
+			for position := range cycle {
+				cycle[position] = filepath.Base(cycle[position])
+			}
+			panic(NewRuntimeError(IMPORTERROR, path.Line,
+				fmt.Sprintf("Import cycle in %s: %s.", i.module.Path, strings.Join(cycle, " → "))))
+		}
+	}
+	source, err := os.ReadFile(file)
+	if err != nil {
+		panic(NewRuntimeError(IMPORTERROR, path.Line, fmt.Sprintf("Can't read module '%s': %v.", path.Literal, err)))
+	}
+	globals := NewEnvironment()
+	defineNatives(globals)
+	module = NewSymModule(strings.TrimSuffix(filepath.Base(file), MODULEEXTENSION), file, globals)
+	i.runModule(module, string(source))
+	i.modules[file] = module
+	return module
+}
+
+// findModule looks next to the importing file first, then in the search path.
+func (i *Interpreter) findModule(path Token) string {
+	name := path.Literal.(string)
+	if filepath.Ext(name) == "" {
+		name += MODULEEXTENSION
+	}
+	if filepath.IsAbs(name) {
+		return name
+	}
+	directories := append([]string{filepath.Dir(i.module.Path)}, i.searchPath...)
+	for _, directory := range directories {
+		file, err := filepath.Abs(filepath.Join(directory, name))
+		if err != nil {
+			continue
+		}
+		_, err = os.Stat(file)
+		if err == nil {
+			return file
+		}
+	}
+	panic(NewRuntimeError(IMPORTERROR, path.Line, fmt.Sprintf("Can't find module '%s'.", path.Literal)))
+}
+
+func (i *Interpreter) runModule(module *SymModule, source string) {
+	lexer := NewLexer(source)
+	parser := NewParser(lexer.scanTokens())
+	statements := parser.parse()
+	NewResolver(i).resolveStatements(statements)
+	previousEnvironment := i.environment
+	previousModule := i.module
+	previousValue := i.currentValue
+	i.importing = append(i.importing, module.Path)
+	defer func() {
+		i.environment = previousEnvironment
+		i.module = previousModule
+		i.currentValue = previousValue
+		i.importing = i.importing[:len(i.importing)-1]
+	}()
+	i.environment = module.environment
+	i.module = module
+	for _, statement := range statements {
+		i.execute(statement)
+	}
+}
@@ -0,0 +1,140 @@
+package sym
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+type NativeFunction struct {
+	Name     string
+	params   []string
+	function func(interpreter *Interpreter, arguments []interface{}) interface{}
+}
+
+func NewNativeFunction(name string, params []string, function func(*Interpreter, []interface{}) interface{}) *NativeFunction {
+	return &NativeFunction{
+		Name:     name,
+		params:   params,
+		function: function,
+	}
+}
+
+func (nf *NativeFunction) Arity() Arity {
+	return NewArity(len(nf.params), len(nf.params))
+}
+
+func (nf *NativeFunction) Parameters() []string {
+	return nf.params
+}
+
+func (nf *NativeFunction) Signature() string {
+	return fmt.Sprintf("%s(%s)", nf.Name, strings.Join(nf.params, ", "))
+}
+
+func (nf *NativeFunction) Call(interpreter *Interpreter, arguments []interface{}) interface{} {
+	return nf.function(interpreter, arguments)
+}
+
+func (nf *NativeFunction) String() string {
+	return fmt.Sprintf("<native %s>", nf.Name)
+}
+
+func defineNatives(environment *Environment) {
+	natives := []*NativeFunction{
+		NewNativeFunction("length", []string{"value"}, nativeLength),
+		NewNativeFunction("append", []string{"list", "value"}, nativeAppend),
+		NewNativeFunction("keys", []string{"map"}, nativeKeys),
+		NewNativeFunction("remove", []string{"map", "key"}, nativeRemove),
+		NewNativeFunction("type", []string{"value"}, nativeType),
+		NewNativeFunction("channel", []string{"type", "capacity"}, nativeChannel),
+		NewNativeFunction("send", []string{"channel", "value"}, nativeSend),
+		NewNativeFunction("receive", []string{"channel"}, nativeReceive),
+		NewNativeFunction("close", []string{"channel"}, nativeClose),
+		NewNativeFunction("select", []string{"channels"}, nativeSelect),
+		NewNativeFunction("wait", []string{"task"}, nativeWait),
+	}
+	for _, native := range natives {
+		environment.define(native.Name, native)
+	}
+}
+
+func nativeLength(interpreter *Interpreter, arguments []interface{}) interface{} {
+	switch value := arguments[0].(type) {
+	case *SymList:
+		return int64(value.length())
+	case *SymMap:
+		return int64(value.length())
+	case string:
+		return int64(len([]rune(value)))
+	default:
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can only take the length of a list, map or string, got %v.", value)))
+	}
+}
+
+func nativeAppend(interpreter *Interpreter, arguments []interface{}) interface{} {
+	list, ok := arguments[0].(*SymList)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can only append to a list, got %v.", arguments[0])))
+	}
+	list.append(arguments[1])
+	return list
+}
+
+func nativeKeys(interpreter *Interpreter, arguments []interface{}) interface{} {
+	symMap, ok := arguments[0].(*SymMap)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can only take the keys of a map, got %v.", arguments[0])))
+	}
+	return NewSymList(symMap.keys())
+}
+
+func nativeRemove(interpreter *Interpreter, arguments []interface{}) interface{} {
+	symMap, ok := arguments[0].(*SymMap)
+	if !ok {
+		panic(NewRuntimeError(TYPEERROR, 0, fmt.Sprintf("Can only remove keys from a map, got %v.", arguments[0])))
+	}
+	return symMap.remove(arguments[1])
+}
+
+func nativeType(interpreter *Interpreter, arguments []interface{}) interface{} {
+	return typeName(arguments[0])
+}
+
+// typeName is the name 'type' returns, which channels also check against.
+func typeName(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case int64, *big.Int, float64:
+		return "number"
+	case string:
+		return "string"
+	case *SymList:
+		return "list"
+	case *SymMap:
+		return "map"
+	case *SymInstance:
+		return value.Class.Name
+	case *SymRecord:
+		return value.Type.Name
+	case *SymVariant:
+		return value.Constructor.Enum.Name
+	case *SymChannel:
+		return "channel"
+	case *SymTask:
+		return "task"
+	case *SymGenerator:
+		return "generator"
+	case *SymModule:
+		return "module"
+	case *RuntimeError:
+		return "error"
+	case SymCallable:
+		return "function"
+	default:
+		return "unknown"
+	}
+}
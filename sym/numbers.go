@@ -0,0 +1,238 @@
+package sym
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Integers are int64 values that grow into *big.Int when they overflow.
+
+const MAXINTEGERBITS = 1 << 20
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int64, *big.Int, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isInteger(value interface{}) bool {
+	switch value.(type) {
+	case int64, *big.Int:
+		return true
+	default:
+		return false
+	}
+}
+
+func normalizeInt(value *big.Int) interface{} {
+	if value.IsInt64() {
+		return value.Int64()
+	}
+	return value
+}
+
+func toBig(value interface{}) *big.Int {
+	switch value := value.(type) {
+	case int64:
+		return big.NewInt(value)
+	case *big.Int:
+		return value
+	default:
+		panic(fmt.Sprintf("Expected an integer, got %v.", value))
+	}
+}
+
+func toFloat(value interface{}) float64 {
+	switch value := value.(type) {
+	case int64:
+		return float64(value)
+	case *big.Int:
+		result, _ := new(big.Float).SetInt(value).Float64()
+		return result
+	case float64:
+		return value
+	default:
+		panic(fmt.Sprintf("Expected a number, got %v.", value))
+	}
+}
+
+// compareNumbers returns -1, 0 or 1. NaN compares unequal to everything.
+func compareNumbers(left interface{}, right interface{}) int {
+	if isInteger(left) && isInteger(right) {
+		leftValue, leftOk := left.(int64)
+		rightValue, rightOk := right.(int64)
+		if leftOk && rightOk {
+			switch {
+			case leftValue < rightValue:
+				return -1
+			case leftValue > rightValue:
+				return 1
+			default:
+				return 0
+			}
+		}
+		return toBig(left).Cmp(toBig(right))
+	}
+	leftValue := toFloat(left)
+	rightValue := toFloat(right)
+	switch {
+	case leftValue < rightValue:
+		return -1
+	case leftValue > rightValue:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func numbersEqual(left interface{}, right interface{}) bool {
+	if math.IsNaN(toFloat(left)) || math.IsNaN(toFloat(right)) {
+		return false
+	}
+	return compareNumbers(left, right) == 0
+}
+
+func negate(value interface{}) interface{} {
+	switch value := value.(type) {
+	case int64:
+		if value == math.MinInt64 {
+			return new(big.Int).Neg(big.NewInt(value))
+		}
+		return -value
+	case *big.Int:
+		return normalizeInt(new(big.Int).Neg(value))
+	default:
+		return -toFloat(value)
+	}
+}
+
+func (i *Interpreter) arithmetic(operator Token, left interface{}, right interface{}) interface{} {
+	if !isNumber(left) || !isNumber(right) {
+		panic(NewRuntimeError(TYPEERROR, operator.Line, fmt.Sprintf("Operands must be two numbers, got %v and %v.", left, right)))
+	}
+	if operator.TokenType == DIVIDE {
+		i.checkDivisor(operator, right)
+		return toFloat(left) / toFloat(right)
+	}
+	if operator.TokenType == POWER && toFloat(left) == 0 && toFloat(right) < 0 {
+		panic(NewRuntimeError(DIVISIONERROR, operator.Line, fmt.Sprintf("Can't raise zero to a negative power with '%s'.", operator.Lexeme)))
+	}
+	if !isInteger(left) || !isInteger(right) {
+		return i.floatArithmetic(operator, toFloat(left), toFloat(right))
+	}
+	leftValue, leftOk := left.(int64)
+	rightValue, rightOk := right.(int64)
+	if leftOk && rightOk {
+		result, ok := i.smallArithmetic(operator, leftValue, rightValue)
+		if ok {
+			return result
+		}
+	}
+	return i.bigArithmetic(operator, toBig(left), toBig(right))
+}
+
+func (i *Interpreter) floatArithmetic(operator Token, left float64, right float64) interface{} {
+	switch operator.TokenType {
+	case PLUS:
+		return left + right
+	case MINUS:
+		return left - right
+	case MULTIPLY:
+		return left * right
+	case MODULO:
+		i.checkDivisor(operator, right)
+		return left - right*math.Floor(left/right)
+	case FLOORDIVIDE:
+		i.checkDivisor(operator, right)
+		return math.Floor(left / right)
+	case POWER:
+		return math.Pow(left, right)
+	}
+	panic("You done messed up.")
+}
+
+// smallArithmetic reports false when the int64 result would overflow.
+func (i *Interpreter) smallArithmetic(operator Token, left int64, right int64) (interface{}, bool) {
+	switch operator.TokenType {
+	case PLUS:
+		result := left + right
+		overflow := (left >= 0) == (right >= 0) && (result >= 0) != (left >= 0)
+		return result, !overflow
+	case MINUS:
+		result := left - right
+		overflow := (left >= 0) != (right >= 0) && (result >= 0) != (left >= 0)
+		return result, !overflow
+	case MULTIPLY:
+		if left == 0 || right == 0 {
+			return int64(0), true
+		}
+		result := left * right
+		overflow := result/right != left ||
+			(left == -1 && right == math.MinInt64) ||
+			(right == -1 && left == math.MinInt64)
+		return result, !overflow
+	case MODULO, FLOORDIVIDE:
+		i.checkDivisor(operator, right)
+		if left == math.MinInt64 && right == -1 {
+			return nil, false
+		}
+		quotient := left / right
+		if left%right != 0 && (left < 0) != (right < 0) {
+			quotient--
+		}
+		if operator.TokenType == FLOORDIVIDE {
+			return quotient, true
+		}
+		return left - quotient*right, true
+	case POWER:
+		if right < 0 {
+			return math.Pow(float64(left), float64(right)), true
+		}
+		return nil, false
+	}
+	panic("You done messed up.")
+}
+
+func (i *Interpreter) bigArithmetic(operator Token, left *big.Int, right *big.Int) interface{} {
+	result := new(big.Int)
+	switch operator.TokenType {
+	case PLUS:
+		result.Add(left, right)
+	case MINUS:
+		result.Sub(left, right)
+	case MULTIPLY:
+		result.Mul(left, right)
+	case MODULO, FLOORDIVIDE:
+		i.checkDivisor(operator, right)
+		remainder := new(big.Int)
+		result.QuoRem(left, right, remainder)
+		if remainder.Sign() != 0 && (left.Sign() < 0) != (right.Sign() < 0) {
+			result.Sub(result, big.NewInt(1))
+			remainder.Add(remainder, right)
+		}
+		if operator.TokenType == MODULO {
+			result = remainder
+		}
+	case POWER:
+		if right.Sign() < 0 {
+			return math.Pow(toFloat(left), toFloat(right))
+		}
+		if left.CmpAbs(big.NewInt(1)) > 0 && (!right.IsInt64() || right.Int64() > MAXINTEGERBITS/int64(left.BitLen()-1)) {
+			panic(NewRuntimeError(OVERFLOWERROR, operator.Line, fmt.Sprintf("Result of '%s' would have more than %d bits.", operator.Lexeme, MAXINTEGERBITS)))
+		}
+		result.Exp(left, right, nil)
+	default:
+		panic("You done messed up.")
+	}
+	return normalizeInt(result)
+}
+
+func (i *Interpreter) checkDivisor(operator Token, divisor interface{}) {
+	if toFloat(divisor) == 0 {
+		panic(NewRuntimeError(DIVISIONERROR, operator.Line, fmt.Sprintf("Division by zero with '%s'.", operator.Lexeme)))
+	}
+}
@@ -1,6 +1,10 @@
 package sym
 
-import "fmt"
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
 
 type Parser struct {
 	tokens  []Token
@@ -30,23 +34,166 @@ func (p *Parser) declaration() (declaration Stmt) {
 			panic(err)
 		}
 	}()
-	if p.match(FUNC) {
+	if p.match(CLASS) {
+		return p.classDeclaration()
+	} else if p.check(FUNC) && p.checkNext(IDENTIFIER) {
+		p.advance()
 		return p.function()
 	} else if p.match(VAR) {
 		return p.varDeclaration()
+	} else if p.match(CONST) {
+		return p.constDeclaration()
+	} else if p.match(ENUM) {
+		return p.enumDeclaration()
+	} else if p.match(RECORD) {
+		return p.recordDeclaration()
+	} else if p.match(EXPORT) {
+		return p.exportDeclaration()
+	} else if p.match(IMPORT) {
+		return p.importDeclaration()
 	} else {
 		return p.statement()
 	}
 }
 
+func (p *Parser) classDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect class name")
+	var superclass *VarExpr
+	if p.match(LESS) {
+		p.consume(IDENTIFIER, "Expect superclass name")
+		superclass = NewVarExpr(p.previous())
+	}
+	p.consume(LEFTBRACE, "Expect '{' before class body")
+	var fields []*VarStmt
+	var methods []*FunctionStmt
+	for !p.check(RIGHTBRACE) && !p.isAtEnd() {
+		if p.match(VAR) {
+			fields = append(fields, p.varDeclaration().(*VarStmt))
+		} else if p.match(FUNC) {
+			methods = append(methods, p.function().(*FunctionStmt))
+		} else {
+			panic(fmt.Sprintf("Expect field or method declaration in class body at line %d.", p.peek().Line))
+		}
+	}
+	p.consume(RIGHTBRACE, "Expect '}' after class body")
+	return NewClassStmt(name, superclass, fields, methods)
+}
+
+func (p *Parser) enumDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect enum name")
+	p.consume(LEFTBRACE, "Expect '{' before enum variants")
+	var variants []*EnumVariant
+	seen := make(map[string]bool)
+	for !p.check(RIGHTBRACE) && !p.isAtEnd() {
+		variant := p.consume(IDENTIFIER, "Expect variant name")
+		if seen[variant.Lexeme] {
+			panic(fmt.Sprintf("Variant '%s' already declared in '%s' at line %d.", variant.Lexeme, name.Lexeme, variant.Line))
+		}
+		seen[variant.Lexeme] = true
+		var fields []Token
+		if p.match(LEFTPARENTHESIS) {
+			fields = p.fieldNames()
+		}
+		variants = append(variants, NewEnumVariant(variant, fields))
+		if !p.match(COMMA) {
+			break
+		}
+	}
+	p.consume(RIGHTBRACE, "Expect '}' after enum variants")
+	return NewEnumStmt(name, variants)
+}
+
+func (p *Parser) recordDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect record name")
+	p.consume(LEFTPARENTHESIS, "Expect '(' after record name")
+	fields := p.fieldNames()
+	p.consume(SEMICOLON, "Expect ';' after record declaration")
+	return NewRecordStmt(name, fields)
+}
+
+// fieldNames never returns nil, so that '()' differs from no parentheses.
+func (p *Parser) fieldNames() []Token {
+	fields := []Token{}
+	seen := make(map[string]bool)
+	if !p.check(RIGHTPARENTHESIS) {
+		for {
+			field := p.consume(IDENTIFIER, "Expect field name")
+			if seen[field.Lexeme] {
+				panic(fmt.Sprintf("Field '%s' already declared at line %d.", field.Lexeme, field.Line))
+			}
+			seen[field.Lexeme] = true
+			fields = append(fields, field)
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+	p.consume(RIGHTPARENTHESIS, "Expect ')' after fields")
+	return fields
+}
+
+func (p *Parser) exportDeclaration() Stmt {
+	keyword := p.previous()
+	declaration := p.declaration()
+	var name Token
+	switch declaration := declaration.(type) {
+	case *ClassStmt:
+		name = declaration.Name
+	case *EnumStmt:
+		name = declaration.Name
+	case *FunctionStmt:
+		name = declaration.Name
+	case *RecordStmt:
+		name = declaration.Name
+	case *VarStmt:
+		name = declaration.Name
+	default:
+		panic(fmt.Sprintf("Expect declaration after '%s' at line %d.", EXPORT, keyword.Line))
+	}
+	return NewExportStmt(keyword, name, declaration)
+}
+
+func (p *Parser) importDeclaration() Stmt {
+	keyword := p.previous()
+	path := p.consume(STRING, fmt.Sprintf("Expect module path after '%s'", IMPORT))
+	var alias Token
+	if p.match(ARROW) {
+		alias = p.consume(IDENTIFIER, fmt.Sprintf("Expect module name after '%s'", ARROW))
+	} else {
+		file := filepath.Base(path.Literal.(string))
+		alias = NewToken(IDENTIFIER, strings.TrimSuffix(file, filepath.Ext(file)), nil, path.Line)
+	}
+	p.consume(SEMICOLON, "Expect ';' after import")
+	return NewImportStmt(keyword, path, alias)
+}
+
 func (p *Parser) function() Stmt {
 	name := p.consume(IDENTIFIER, "Expect function name")
-	p.consume(LEFTPARENTHESIS, "Expect '(' after function name")
+	return p.functionBody(name)
+}
+
+func (p *Parser) functionBody(name Token) *FunctionStmt {
+	p.consume(LEFTPARENTHESIS, fmt.Sprintf("Expect '(' after '%s'", name.Lexeme))
 	var parameters []Token
+	var defaults []Expr
+	var rest *Token
 	if !p.check(RIGHTPARENTHESIS) {
 		for {
+			if p.match(ELLIPSIS) {
+				parameter := p.consume(IDENTIFIER, fmt.Sprintf("Expect parameter name after '%s'", ELLIPSIS))
+				rest = &parameter
+				break
+			}
 			parameter := p.consume(IDENTIFIER, "Expect parameter name")
+			var defaultValue Expr
+			if p.match(ASSIGN) {
+				defaultValue = p.conditional()
+			} else if len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+				panic(fmt.Sprintf("Expect default value for '%s' after a parameter with a default at line %d.",
+					parameter.Lexeme, parameter.Line))
+			}
 			parameters = append(parameters, parameter)
+			defaults = append(defaults, defaultValue)
 			if !p.match(COMMA) {
 				break
 			}
@@ -55,17 +202,29 @@ func (p *Parser) function() Stmt {
 	p.consume(RIGHTPARENTHESIS, "Expect ')' after parameters")
 	p.consume(LEFTBRACE, "Expect '{' before function body")
 	body := p.block()
-	return NewFunctionStmt(name, parameters, body)
+	return NewFunctionStmt(name, parameters, defaults, rest, body)
 }
 
 func (p *Parser) varDeclaration() Stmt {
 	name := p.consume(IDENTIFIER, "Expect variable name")
+	return p.varInitializer(name)
+}
+
+func (p *Parser) varInitializer(name Token) Stmt {
 	var initializer Expr
 	if p.match(ASSIGN) {
 		initializer = p.expression()
 	}
 	p.consume(SEMICOLON, "Expect ';' after variable declaration")
-	return NewVarStmt(name, initializer)
+	return NewVarStmt(name, initializer, false)
+}
+
+func (p *Parser) constDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect constant name")
+	p.consume(ASSIGN, fmt.Sprintf("Expect '%s' after constant name", ASSIGN))
+	initializer := p.expression()
+	p.consume(SEMICOLON, "Expect ';' after constant declaration")
+	return NewVarStmt(name, initializer, true)
 }
 
 func (p *Parser) statement() Stmt {
@@ -74,14 +233,28 @@ func (p *Parser) statement() Stmt {
 		return NewBlockStmt(block)
 	} else if p.match(BREAK) {
 		return p.breakStatement()
+	} else if p.match(CONTINUE) {
+		return p.continueStatement()
 	} else if p.match(IF) {
 		return p.ifStatement()
+	} else if p.match(LABEL) {
+		label := p.previous()
+		p.consume(LOOP, fmt.Sprintf("Expect '%s' after loop label", LOOP))
+		return p.loopStatement(&label)
 	} else if p.match(LOOP) {
-		return p.loopStatement()
+		return p.loopStatement(nil)
+	} else if p.match(MATCH) {
+		return p.matchStatement()
 	} else if p.match(PRINT) {
 		return p.printStatement()
 	} else if p.match(RETURN) {
 		return p.returnStatement()
+	} else if p.match(THROW) {
+		return p.throwStatement()
+	} else if p.match(TRY) {
+		return p.tryStatement()
+	} else if p.match(YIELD) {
+		return p.yieldStatement()
 	} else {
 		return p.expressionStatement()
 	}
@@ -89,8 +262,24 @@ func (p *Parser) statement() Stmt {
 
 func (p *Parser) breakStatement() Stmt {
 	keyword := p.previous()
+	label := p.loopLabel()
 	p.consume(SEMICOLON, fmt.Sprintf("Expect ';' after '%s'", BREAK))
-	return NewBreakStmt(keyword)
+	return NewBreakStmt(keyword, label)
+}
+
+func (p *Parser) continueStatement() Stmt {
+	keyword := p.previous()
+	label := p.loopLabel()
+	p.consume(SEMICOLON, fmt.Sprintf("Expect ';' after '%s'", CONTINUE))
+	return NewContinueStmt(keyword, label)
+}
+
+func (p *Parser) loopLabel() *Token {
+	if p.match(LABEL) {
+		label := p.previous()
+		return &label
+	}
+	return nil
 }
 
 func (p *Parser) ifStatement() Stmt {
@@ -98,12 +287,37 @@ func (p *Parser) ifStatement() Stmt {
 	condition := p.expression()
 	p.consume(RIGHTPARENTHESIS, fmt.Sprintf("Expect ')' after '%s' condition", IF))
 	then := p.statement()
-	return NewIfStmt(condition, then)
-}
-
-func (p *Parser) loopStatement() Stmt {
+	var elseBranch Stmt
+	if p.match(ELSE) {
+		elseBranch = p.statement()
+	}
+	return NewIfStmt(condition, then, elseBranch)
+}
+
+func (p *Parser) loopStatement(label *Token) Stmt {
+	var initializer Stmt
+	var condition Expr
+	var increment Expr
+	var iterable Expr
+	if p.match(LEFTPARENTHESIS) {
+		if p.match(VAR) {
+			name := p.consume(IDENTIFIER, "Expect variable name")
+			if p.match(IN) {
+				initializer = NewVarStmt(name, nil, false)
+				iterable = p.expression()
+			} else {
+				initializer = p.varInitializer(name)
+				condition = p.expression()
+				p.consume(SEMICOLON, fmt.Sprintf("Expect ';' after '%s' condition", LOOP))
+				increment = p.expression()
+			}
+		} else {
+			condition = p.expression()
+		}
+		p.consume(RIGHTPARENTHESIS, fmt.Sprintf("Expect ')' after '%s' clauses", LOOP))
+	}
 	body := p.statement()
-	return NewLoopStmt(body)
+	return NewLoopStmt(label, initializer, condition, increment, iterable, body)
 }
 
 func (p *Parser) printStatement() Stmt {
@@ -122,6 +336,181 @@ func (p *Parser) returnStatement() Stmt {
 	return NewReturnStmt(keyword, value)
 }
 
+func (p *Parser) throwStatement() Stmt {
+	keyword := p.previous()
+	value := p.expression()
+	p.consume(SEMICOLON, "Expect ';' after thrown value")
+	return NewThrowStmt(keyword, value)
+}
+
+func (p *Parser) tryStatement() Stmt {
+	keyword := p.previous()
+	p.consume(LEFTBRACE, fmt.Sprintf("Expect '{' after '%s'", TRY))
+	body := NewBlockStmt(p.block())
+	var catchName *Token
+	var catch *BlockStmt
+	if p.match(CATCH) {
+		if p.match(LEFTPARENTHESIS) {
+			name := p.consume(IDENTIFIER, "Expect name of caught value")
+			catchName = &name
+			p.consume(RIGHTPARENTHESIS, "Expect ')' after name of caught value")
+		}
+		p.consume(LEFTBRACE, fmt.Sprintf("Expect '{' after '%s'", CATCH))
+		catch = NewBlockStmt(p.block())
+	}
+	var finally *BlockStmt
+	if p.match(FINALLY) {
+		p.consume(LEFTBRACE, fmt.Sprintf("Expect '{' after '%s'", FINALLY))
+		finally = NewBlockStmt(p.block())
+	}
+	if catch == nil && finally == nil {
+		panic(fmt.Sprintf("Expect '%s' or '%s' after '%s' block at line %d.", CATCH, FINALLY, TRY, p.peek().Line))
+	}
+	return NewTryStmt(keyword, body, catchName, catch, finally)
+}
+
+func (p *Parser) matchStatement() Stmt {
+	keyword, subject := p.matchSubject()
+	var arms []*MatchArm
+	for !p.check(RIGHTBRACE) && !p.isAtEnd() {
+		pattern, guard := p.matchCase()
+		arms = append(arms, NewMatchArm(pattern, guard, p.statement(), nil))
+	}
+	p.consume(RIGHTBRACE, fmt.Sprintf("Expect '}' after '%s' arms", MATCH))
+	return NewMatchStmt(keyword, subject, arms)
+}
+
+func (p *Parser) matchExpression() Expr {
+	keyword, subject := p.matchSubject()
+	var arms []*MatchArm
+	for !p.check(RIGHTBRACE) && !p.isAtEnd() {
+		pattern, guard := p.matchCase()
+		arms = append(arms, NewMatchArm(pattern, guard, nil, p.expression()))
+		if !p.match(COMMA) {
+			break
+		}
+	}
+	p.consume(RIGHTBRACE, fmt.Sprintf("Expect '}' after '%s' arms", MATCH))
+	return NewMatchExpr(keyword, subject, arms)
+}
+
+func (p *Parser) matchSubject() (Token, Expr) {
+	keyword := p.previous()
+	p.consume(LEFTPARENTHESIS, fmt.Sprintf("Expect '(' after '%s'", MATCH))
+	subject := p.expression()
+	p.consume(RIGHTPARENTHESIS, fmt.Sprintf("Expect ')' after '%s' subject", MATCH))
+	p.consume(LEFTBRACE, fmt.Sprintf("Expect '{' before '%s' arms", MATCH))
+	return keyword, subject
+}
+
+func (p *Parser) matchCase() (Pattern, Expr) {
+	pattern := p.pattern()
+	var guard Expr
+	if p.match(IF) {
+		guard = p.expression()
+	}
+	p.consume(ARROW, fmt.Sprintf("Expect '%s' after pattern", ARROW))
+	return pattern, guard
+}
+
+func (p *Parser) pattern() Pattern {
+	if p.match(VAR) {
+		return NewBindingPattern(p.consume(IDENTIFIER, "Expect name to bind"))
+	} else if p.check(IDENTIFIER) && p.peek().Lexeme == "_" {
+		return NewWildcardPattern(p.advance())
+	} else if p.match(LEFTBRACKET) {
+		return p.collectionPattern()
+	} else if p.check(IDENTIFIER) {
+		pattern := p.constructorPattern()
+		if pattern != nil {
+			return pattern
+		}
+	}
+	return NewValuePattern(p.term())
+}
+
+// constructorPattern backs up and returns nil when the name isn't followed by '('.
+func (p *Parser) constructorPattern() Pattern {
+	start := p.current
+	var constructor Expr = NewVarExpr(p.advance())
+	for p.match(DOT) {
+		name := p.consume(IDENTIFIER, "Expect property name after '.'")
+		constructor = NewGetExpr(constructor, name)
+	}
+	if !p.match(LEFTPARENTHESIS) {
+		p.current = start
+		return nil
+	}
+	parenthesis := p.previous()
+	var fields []Pattern
+	if !p.check(RIGHTPARENTHESIS) {
+		for {
+			fields = append(fields, p.pattern())
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+	p.consume(RIGHTPARENTHESIS, "Expect ')' after constructor pattern fields")
+	return NewConstructorPattern(constructor, parenthesis, fields)
+}
+
+func (p *Parser) collectionPattern() Pattern {
+	bracket := p.previous()
+	if p.match(ARROW) {
+		p.consume(RIGHTBRACKET, "Expect ']' after empty map pattern")
+		return NewMapPattern(bracket, nil, nil)
+	}
+	var elements []Pattern
+	var rest *Token
+	if !p.check(RIGHTBRACKET) {
+		for {
+			if p.match(ELLIPSIS) {
+				name := p.consume(IDENTIFIER, fmt.Sprintf("Expect name after '%s'", ELLIPSIS))
+				rest = &name
+				break
+			}
+			element := p.pattern()
+			if len(elements) == 0 && p.match(ARROW) {
+				return p.mapPattern(bracket, element)
+			}
+			elements = append(elements, element)
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+	p.consume(RIGHTBRACKET, "Expect ']' after list pattern")
+	return NewListPattern(bracket, elements, rest)
+}
+
+func (p *Parser) mapPattern(bracket Token, key Pattern) Pattern {
+	var keys []Expr
+	var values []Pattern
+	for {
+		value, ok := key.(*ValuePattern)
+		if !ok {
+			panic(fmt.Sprintf("Expect a value as map pattern key at line %d.", p.previous().Line))
+		}
+		keys = append(keys, value.Value)
+		values = append(values, p.pattern())
+		if !p.match(COMMA) {
+			break
+		}
+		key = NewValuePattern(p.term())
+		p.consume(ARROW, fmt.Sprintf("Expect '%s' after map key", ARROW))
+	}
+	p.consume(RIGHTBRACKET, "Expect ']' after map pattern")
+	return NewMapPattern(bracket, keys, values)
+}
+
+func (p *Parser) yieldStatement() Stmt {
+	keyword := p.previous()
+	value := p.expression()
+	p.consume(SEMICOLON, "Expect ';' after yield value")
+	return NewYieldStmt(keyword, value)
+}
+
 func (p *Parser) block() []Stmt {
 	var statements []Stmt
 	for !p.check(RIGHTBRACE) && !p.isAtEnd() {
@@ -143,15 +532,57 @@ func (p *Parser) expression() Expr {
 }
 
 func (p *Parser) assignment() Expr {
-	expr := p.or()
+	expr := p.conditional()
 	if p.match(ASSIGN) {
 		assign := p.previous()
-		value := p.or()
-		varExpr, ok := expr.(*VarExpr)
-		if ok {
-			return NewAssignExpr(varExpr.Name, value)
+		value := p.conditional()
+		switch target := expr.(type) {
+		case *VarExpr:
+			return NewAssignExpr(target.Name, value)
+		case *GetExpr:
+			return NewSetExpr(target.Object, target.Name, value)
+		case *IndexExpr:
+			return NewIndexSetExpr(target.Object, target.Bracket, target.Index, value)
 		}
 		panic(fmt.Sprintf("Invalid assignment target '%v'", assign))
+	} else if p.match(PLUSASSIGN, MINUSASSIGN, MULTIPLYASSIGN, DIVIDEASSIGN, MODULOASSIGN, POWERASSIGN, FLOORDIVIDEASSIGN) {
+		assign := p.previous()
+		value := p.conditional()
+		return p.compoundAssignment(expr, assign, value)
+	}
+	return expr
+}
+
+var compoundOperators = map[string]string{
+	PLUSASSIGN:        PLUS,
+	MINUSASSIGN:       MINUS,
+	MULTIPLYASSIGN:    MULTIPLY,
+	DIVIDEASSIGN:      DIVIDE,
+	MODULOASSIGN:      MODULO,
+	POWERASSIGN:       POWER,
+	FLOORDIVIDEASSIGN: FLOORDIVIDE,
+	INCREMENT:         PLUS,
+	DECREMENT:         MINUS,
+}
+
+func (p *Parser) compoundAssignment(target Expr, assign Token, value Expr) Expr {
+	switch target.(type) {
+	case *VarExpr, *IndexExpr, *GetExpr:
+		operatorType := compoundOperators[assign.TokenType]
+		operator := NewToken(operatorType, operatorType, nil, assign.Line)
+		postfix := assign.TokenType == INCREMENT || assign.TokenType == DECREMENT
+		return NewCompoundAssignExpr(target, operator, value, postfix)
+	}
+	panic(fmt.Sprintf("Invalid assignment target '%v'", assign))
+}
+
+func (p *Parser) conditional() Expr {
+	expr := p.or()
+	if p.match(IF) {
+		then := p.expression()
+		p.consume(ELSE, fmt.Sprintf("Expect '%s' after then branch of conditional expression", ELSE))
+		elseBranch := p.conditional()
+		expr = NewConditionalExpr(expr, then, elseBranch)
 	}
 	return expr
 }
@@ -188,7 +619,7 @@ func (p *Parser) equality() Expr {
 
 func (p *Parser) comparison() Expr {
 	expr := p.term()
-	for p.match(GREATER, GREATEREQUAL, LESS, LESSEQUAL) {
+	for p.match(GREATER, GREATEREQUAL, LESS, LESSEQUAL, IN) {
 		operator := p.previous()
 		right := p.term()
 		expr = NewBinaryExpr(expr, operator, right)
@@ -198,17 +629,26 @@ func (p *Parser) comparison() Expr {
 
 func (p *Parser) term() Expr {
 	expr := p.factor()
-	for p.match(MINUS, PLUS) {
+	for p.match(MINUS, PLUS, DECREMENT) {
 		operator := p.previous()
+		if operator.TokenType == DECREMENT {
+			expr = NewBinaryExpr(expr, p.minus(operator), NewUnaryExpr(p.minus(operator), p.factor()))
+			continue
+		}
 		right := p.factor()
 		expr = NewBinaryExpr(expr, operator, right)
 	}
 	return expr
 }
 
+// minus stands for one of the two minus signs of a '--' that isn't a step.
+func (p *Parser) minus(token Token) Token {
+	return NewToken(MINUS, MINUS, nil, token.Line)
+}
+
 func (p *Parser) factor() Expr {
 	expr := p.unary()
-	for p.match(DIVIDE, MULTIPLY) {
+	for p.match(DIVIDE, MULTIPLY, MODULO, FLOORDIVIDE) {
 		operator := p.previous()
 		right := p.unary()
 		expr = NewBinaryExpr(expr, operator, right)
@@ -221,8 +661,53 @@ func (p *Parser) unary() Expr {
 		operator := p.previous()
 		right := p.unary()
 		return NewUnaryExpr(operator, right)
+	} else if p.match(DECREMENT) {
+		operator := p.minus(p.previous())
+		return NewUnaryExpr(operator, NewUnaryExpr(operator, p.unary()))
+	} else if p.match(SPAWN) {
+		keyword := p.previous()
+		call, ok := p.call().(*CallExpr)
+		if !ok {
+			panic(fmt.Sprintf("Expect call after '%s' at line %d.", SPAWN, keyword.Line))
+		}
+		return NewSpawnExpr(keyword, call)
+	}
+	return p.power()
+}
+
+// power binds tighter than unary minus, so -2 ^ 2 is -4.
+func (p *Parser) power() Expr {
+	expr := p.postfix()
+	if p.match(POWER) {
+		operator := p.previous()
+		right := p.unary()
+		expr = NewBinaryExpr(expr, operator, right)
+	}
+	return expr
+}
+
+// postfix leaves a '--' followed by an operand, as in 'a--3', to term.
+func (p *Parser) postfix() Expr {
+	expr := p.call()
+	if !p.check(INCREMENT) && !p.check(DECREMENT) {
+		return expr
+	}
+	if p.startsOperand(p.tokens[p.current+1]) {
+		if p.check(INCREMENT) {
+			panic(fmt.Sprintf("Unexpected operand after '%s' at line %d.", INCREMENT, p.peek().Line))
+		}
+		return expr
 	}
-	return p.call()
+	step := p.advance()
+	return p.compoundAssignment(expr, step, NewLiteralExpr(int64(1)))
+}
+
+func (p *Parser) startsOperand(token Token) bool {
+	switch token.TokenType {
+	case FALSE, TRUE, NIL, NUMBER, STRING, TEMPLATE, SUPER, THIS, IDENTIFIER, LEFTPARENTHESIS, FUNC:
+		return true
+	}
+	return false
 }
 
 func (p *Parser) call() Expr {
@@ -230,6 +715,14 @@ func (p *Parser) call() Expr {
 	for {
 		if p.match(LEFTPARENTHESIS) {
 			expr = p.finishCall(expr)
+		} else if p.match(LEFTBRACKET) {
+			bracket := p.previous()
+			index := p.expression()
+			p.consume(RIGHTBRACKET, "Expect ']' after index")
+			expr = NewIndexExpr(expr, bracket, index)
+		} else if p.match(DOT) {
+			name := p.consume(IDENTIFIER, "Expect property name after '.'")
+			expr = NewGetExpr(expr, name)
 		} else {
 			break
 		}
@@ -239,17 +732,28 @@ func (p *Parser) call() Expr {
 
 func (p *Parser) finishCall(callee Expr) Expr {
 	var arguments []Expr
+	var names []Token
+	var namedArguments []Expr
 	if !p.check(RIGHTPARENTHESIS) {
 		for {
-			expr := p.expression()
-			arguments = append(arguments, expr)
+			if p.check(IDENTIFIER) && p.checkNext(ASSIGN) {
+				name := p.advance()
+				p.advance()
+				names = append(names, name)
+				namedArguments = append(namedArguments, p.expression())
+			} else if len(names) > 0 {
+				panic(fmt.Sprintf("Expect named argument after '%s' at line %d.",
+					names[len(names)-1].Lexeme, p.peek().Line))
+			} else {
+				arguments = append(arguments, p.expression())
+			}
 			if !p.match(COMMA) {
 				break
 			}
 		}
 	}
 	parenthesis := p.consume(RIGHTPARENTHESIS, "Expect ')' after arguments")
-	return NewCallExpr(callee, parenthesis, arguments)
+	return NewCallExpr(callee, parenthesis, arguments, names, namedArguments)
 }
 
 func (p *Parser) primary() Expr {
@@ -261,13 +765,89 @@ func (p *Parser) primary() Expr {
 		return NewLiteralExpr(nil)
 	} else if p.match(NUMBER, STRING) {
 		return NewLiteralExpr(p.previous().Literal)
+	} else if p.match(TEMPLATE) {
+		return p.interpolation()
+	} else if p.match(MATCH) {
+		return p.matchExpression()
+	} else if p.match(SUPER) {
+		keyword := p.previous()
+		p.consume(DOT, fmt.Sprintf("Expect '.' after '%s'", SUPER))
+		method := p.consume(IDENTIFIER, "Expect superclass method name")
+		return NewSuperExpr(keyword, method)
+	} else if p.match(THIS) {
+		return NewThisExpr(p.previous())
 	} else if p.match(IDENTIFIER) {
 		return NewVarExpr(p.previous())
+	} else if p.match(LEFTPARENTHESIS) {
+		expr := p.expression()
+		p.consume(RIGHTPARENTHESIS, "Expect ')' after expression")
+		return expr
+	} else if p.match(LEFTBRACKET) {
+		return p.list()
+	} else if p.match(FUNC) {
+		return NewFunctionExpr(p.functionBody(p.previous()))
 	} else {
 		panic(fmt.Sprintf("Expected expression at line %d.", p.peek().Line))
 	}
 }
 
+// interpolation lexes each expression on its own line so errors point into the source.
+func (p *Parser) interpolation() Expr {
+	token := p.previous()
+	var parts []Expr
+	for _, part := range token.Literal.([]StringPart) {
+		if !part.IsExpression {
+			if part.Text != "" {
+				parts = append(parts, NewLiteralExpr(part.Text))
+			}
+			continue
+		}
+		lexer := NewLexer(part.Text)
+		lexer.line = part.Line
+		parser := NewParser(lexer.scanTokens())
+		parts = append(parts, parser.expression())
+		if !parser.isAtEnd() {
+			panic(fmt.Sprintf("Expect ')' after interpolated expression at line %d.", parser.peek().Line))
+		}
+	}
+	return NewInterpolationExpr(token, parts)
+}
+
+func (p *Parser) list() Expr {
+	bracket := p.previous()
+	if p.match(ARROW) {
+		p.consume(RIGHTBRACKET, "Expect ']' after empty map")
+		return NewMapExpr(bracket, nil, nil)
+	}
+	var elements []Expr
+	if !p.check(RIGHTBRACKET) {
+		for {
+			element := p.expression()
+			if len(elements) == 0 && p.match(ARROW) {
+				return p.symMap(bracket, element)
+			}
+			elements = append(elements, element)
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+	p.consume(RIGHTBRACKET, "Expect ']' after list elements")
+	return NewListExpr(bracket, elements)
+}
+
+func (p *Parser) symMap(bracket Token, key Expr) Expr {
+	keys := []Expr{key}
+	values := []Expr{p.expression()}
+	for p.match(COMMA) {
+		keys = append(keys, p.expression())
+		p.consume(ARROW, fmt.Sprintf("Expect '%s' after map key", ARROW))
+		values = append(values, p.expression())
+	}
+	p.consume(RIGHTBRACKET, "Expect ']' after map entries")
+	return NewMapExpr(bracket, keys, values)
+}
+
 func (p *Parser) advance() Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -282,6 +862,13 @@ func (p *Parser) check(tokenType string) bool {
 	return p.peek().TokenType == tokenType
 }
 
+func (p *Parser) checkNext(tokenType string) bool {
+	if p.isAtEnd() || p.current+1 >= len(p.tokens) {
+		return false
+	}
+	return p.tokens[p.current+1].TokenType == tokenType
+}
+
 func (p *Parser) consume(tokenType string, message string) Token {
 	if p.check(tokenType) {
 		return p.advance()
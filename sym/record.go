@@ -0,0 +1,104 @@
+package sym
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type SymRecordType struct {
+	Name   string
+	Fields []string
+}
+
+func NewSymRecordType(statement *RecordStmt) *SymRecordType {
+	fields := make([]string, len(statement.Fields))
+	for index, field := range statement.Fields {
+		fields[index] = field.Lexeme
+	}
+	return &SymRecordType{
+		Name:   statement.Name.Lexeme,
+		Fields: fields,
+	}
+}
+
+func (srt *SymRecordType) Arity() Arity {
+	return NewArity(len(srt.Fields), len(srt.Fields))
+}
+
+func (srt *SymRecordType) Call(interpreter *Interpreter, arguments []interface{}) interface{} {
+	return NewSymRecord(srt, append([]interface{}{}, arguments...))
+}
+
+func (srt *SymRecordType) Parameters() []string {
+	return srt.Fields
+}
+
+func (srt *SymRecordType) Signature() string {
+	return fmt.Sprintf("%s(%s)", srt.Name, strings.Join(srt.Fields, ", "))
+}
+
+func (srt *SymRecordType) String() string {
+	return fmt.Sprintf("<® %s>", srt.Name)
+}
+
+type SymRecord struct {
+	Type   *SymRecordType
+	Values []interface{}
+	lock   sync.RWMutex
+}
+
+func NewSymRecord(recordType *SymRecordType, values []interface{}) *SymRecord {
+	return &SymRecord{
+		Type:   recordType,
+		Values: values,
+	}
+}
+
+func (sr *SymRecord) field(name Token) int {
+	for index, field := range sr.Type.Fields {
+		if field == name.Lexeme {
+			return index
+		}
+	}
+	panic(NewRuntimeError(PROPERTYERROR, name.Line, fmt.Sprintf("Undefined field '%s' in %s.", name.Lexeme, sr.Type.Signature())))
+}
+
+func (sr *SymRecord) get(name Token) interface{} {
+	index := sr.field(name)
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+	return sr.Values[index]
+}
+
+func (sr *SymRecord) set(name Token, value interface{}) {
+	index := sr.field(name)
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+	sr.Values[index] = value
+}
+
+// values returns a copy of the field values that other tasks can't change.
+func (sr *SymRecord) values() []interface{} {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+	return append([]interface{}{}, sr.Values...)
+}
+
+func (sr *SymRecord) String() string {
+	return sr.format(make(map[interface{}]bool))
+}
+
+func (sr *SymRecord) format(visiting map[interface{}]bool) string {
+	if visiting[sr] {
+		return sr.Type.Name + "{...}"
+	}
+	visiting[sr] = true
+	defer delete(visiting, sr)
+	values := sr.values()
+	fields := make([]string, len(values))
+	for index, value := range values {
+		fields[index] = sr.Type.Fields[index] + ": " + formatElement(value, visiting)
+	}
+	return sr.Type.Name + "{" + strings.Join(fields, ", ") + "}"
+}
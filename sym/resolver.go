@@ -2,15 +2,42 @@ package sym
 
 import "fmt"
 
+type FunctionType = string
+
+const (
+	NOFUNCTION  FunctionType = "NONE"
+	FUNCTION    FunctionType = "FUNCTION"
+	INITIALIZER FunctionType = "INITIALIZER"
+	METHOD      FunctionType = "METHOD"
+)
+
+type ClassType = string
+
+const (
+	NOCLASS  ClassType = "NONE"
+	INCLASS  ClassType = "CLASS"
+	SUBCLASS ClassType = "SUBCLASS"
+)
+
 type Resolver struct {
-	interpreter *Interpreter
-	scopes      []map[string]bool
+	interpreter     *Interpreter
+	scopes          []map[string]bool
+	constants       []map[string]bool
+	loops           []string
+	tries           int
+	declaration     *FunctionStmt
+	currentFunction FunctionType
+	currentClass    ClassType
 }
 
 func NewResolver(interpreter *Interpreter) *Resolver {
 	return &Resolver{
-		interpreter: interpreter,
-		scopes:      make([]map[string]bool, 0),
+		interpreter:     interpreter,
+		scopes:          make([]map[string]bool, 0),
+		constants:       make([]map[string]bool, 0),
+		loops:           make([]string, 0),
+		currentFunction: NOFUNCTION,
+		currentClass:    NOCLASS,
 	}
 }
 
@@ -34,12 +61,33 @@ func (r *Resolver) resolveExpression(expression Expr) {
 	expression.Accept(r)
 }
 
-func (r *Resolver) resolveFunction(function *FunctionStmt) {
+func (r *Resolver) resolveFunction(function *FunctionStmt, functionType FunctionType) {
+	enclosingLoops := r.loops
+	enclosingTries := r.tries
+	enclosingDeclaration := r.declaration
+	enclosingFunction := r.currentFunction
+	r.loops = make([]string, 0)
+	r.tries = 0
+	r.declaration = function
+	r.currentFunction = functionType
+	defer func() {
+		r.loops = enclosingLoops
+		r.tries = enclosingTries
+		r.declaration = enclosingDeclaration
+		r.currentFunction = enclosingFunction
+	}()
 	r.beginScope()
-	for _, param := range function.Params {
+	for i, param := range function.Params {
+		if function.Defaults[i] != nil {
+			r.resolveExpression(function.Defaults[i])
+		}
 		r.declare(param)
 		r.define(param)
 	}
+	if function.Rest != nil {
+		r.declare(*function.Rest)
+		r.define(*function.Rest)
+	}
 	r.resolveStatements(function.Body)
 	r.endScope()
 }
@@ -54,12 +102,30 @@ func (r *Resolver) resolveLocal(expression Expr, name Token) {
 	}
 }
 
+// resolveLoopAction checks that 'Ɵ' and '↻' are in a loop with their label.
+func (r *Resolver) resolveLoopAction(keyword Token, label *Token) {
+	if len(r.loops) == 0 {
+		panic(fmt.Sprintf("Can't use '%s' outside of a loop at line %d.", keyword.Lexeme, keyword.Line))
+	}
+	if label == nil {
+		return
+	}
+	for _, loop := range r.loops {
+		if loop == label.Lexeme {
+			return
+		}
+	}
+	panic(fmt.Sprintf("Undefined loop label '%s' at line %d.", label.Lexeme, label.Line))
+}
+
 func (r *Resolver) beginScope() {
 	r.scopes = append(r.scopes, make(map[string]bool))
+	r.constants = append(r.constants, make(map[string]bool))
 }
 
 func (r *Resolver) endScope() {
 	r.scopes = r.scopes[:len(r.scopes)-1]
+	r.constants = r.constants[:len(r.constants)-1]
 }
 
 func (r *Resolver) declare(name Token) {
@@ -80,8 +146,22 @@ func (r *Resolver) define(name Token) {
 	r.scopes[len(r.scopes)-1][name.Lexeme] = true
 }
 
+// checkAssignable rejects assignments to local constants.
+func (r *Resolver) checkAssignable(name Token) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		_, ok := r.scopes[i][name.Lexeme]
+		if ok {
+			if r.constants[i][name.Lexeme] {
+				panic(fmt.Sprintf("Can't assign to constant '%s' at line %d.", name.Lexeme, name.Line))
+			}
+			return
+		}
+	}
+}
+
 func (r *Resolver) visitAssignExpr(expression *AssignExpr) interface{} {
 	r.resolveExpression(expression.Value)
+	r.checkAssignable(expression.Name)
 	r.resolveLocal(expression, expression.Name)
 	return nil
 }
@@ -97,6 +177,68 @@ func (r *Resolver) visitCallExpr(expression *CallExpr) interface{} {
 	for _, argument := range expression.Arguments {
 		r.resolveExpression(argument)
 	}
+	for _, argument := range expression.NamedArguments {
+		r.resolveExpression(argument)
+	}
+	return nil
+}
+
+func (r *Resolver) visitCompoundAssignExpr(expression *CompoundAssignExpr) interface{} {
+	r.resolveExpression(expression.Value)
+	switch target := expression.Target.(type) {
+	case *VarExpr:
+		r.checkAssignable(target.Name)
+		r.resolveLocal(expression, target.Name)
+	case *IndexExpr:
+		r.resolveExpression(target.Object)
+		r.resolveExpression(target.Index)
+	case *GetExpr:
+		r.resolveExpression(target.Object)
+	}
+	return nil
+}
+
+func (r *Resolver) visitConditionalExpr(expression *ConditionalExpr) interface{} {
+	r.resolveExpression(expression.Condition)
+	r.resolveExpression(expression.Then)
+	r.resolveExpression(expression.Else)
+	return nil
+}
+
+func (r *Resolver) visitFunctionExpr(expression *FunctionExpr) interface{} {
+	r.resolveFunction(expression.Declaration, FUNCTION)
+	return nil
+}
+
+func (r *Resolver) visitGetExpr(expression *GetExpr) interface{} {
+	r.resolveExpression(expression.Object)
+	return nil
+}
+
+func (r *Resolver) visitIndexExpr(expression *IndexExpr) interface{} {
+	r.resolveExpression(expression.Object)
+	r.resolveExpression(expression.Index)
+	return nil
+}
+
+func (r *Resolver) visitIndexSetExpr(expression *IndexSetExpr) interface{} {
+	r.resolveExpression(expression.Object)
+	r.resolveExpression(expression.Index)
+	r.resolveExpression(expression.Value)
+	return nil
+}
+
+func (r *Resolver) visitInterpolationExpr(expression *InterpolationExpr) interface{} {
+	for _, part := range expression.Parts {
+		r.resolveExpression(part)
+	}
+	return nil
+}
+
+func (r *Resolver) visitListExpr(expression *ListExpr) interface{} {
+	for _, element := range expression.Elements {
+		r.resolveExpression(element)
+	}
 	return nil
 }
 
@@ -110,6 +252,51 @@ func (r *Resolver) visitLogicalExpr(expression *LogicalExpr) interface{} {
 	return nil
 }
 
+func (r *Resolver) visitMapExpr(expression *MapExpr) interface{} {
+	for index := range expression.Keys {
+		r.resolveExpression(expression.Keys[index])
+		r.resolveExpression(expression.Values[index])
+	}
+	return nil
+}
+
+func (r *Resolver) visitMatchExpr(expression *MatchExpr) interface{} {
+	r.resolveExpression(expression.Subject)
+	for _, arm := range expression.Arms {
+		r.resolveArm(arm)
+	}
+	return nil
+}
+
+func (r *Resolver) visitSetExpr(expression *SetExpr) interface{} {
+	r.resolveExpression(expression.Value)
+	r.resolveExpression(expression.Object)
+	return nil
+}
+
+func (r *Resolver) visitSpawnExpr(expression *SpawnExpr) interface{} {
+	r.resolveExpression(expression.Call)
+	return nil
+}
+
+func (r *Resolver) visitSuperExpr(expression *SuperExpr) interface{} {
+	if r.currentClass == NOCLASS {
+		panic(fmt.Sprintf("Can't use '%s' outside of a class at line %d.", SUPER, expression.Keyword.Line))
+	} else if r.currentClass != SUBCLASS {
+		panic(fmt.Sprintf("Can't use '%s' in a class with no superclass at line %d.", SUPER, expression.Keyword.Line))
+	}
+	r.resolveLocal(expression, expression.Keyword)
+	return nil
+}
+
+func (r *Resolver) visitThisExpr(expression *ThisExpr) interface{} {
+	if r.currentClass == NOCLASS {
+		panic(fmt.Sprintf("Can't use '%s' outside of a class at line %d.", THIS, expression.Keyword.Line))
+	}
+	r.resolveLocal(expression, expression.Keyword)
+	return nil
+}
+
 func (r *Resolver) visitUnaryExpr(expression *UnaryExpr) interface{} {
 	r.resolveExpression(expression.Right)
 	return nil
@@ -134,6 +321,62 @@ func (r *Resolver) visitBlockStmt(statement *BlockStmt) interface{} {
 }
 
 func (r *Resolver) visitBreakStmt(statement *BreakStmt) interface{} {
+	r.resolveLoopAction(statement.Token, statement.Label)
+	return nil
+}
+
+func (r *Resolver) visitClassStmt(statement *ClassStmt) interface{} {
+	enclosingClass := r.currentClass
+	r.currentClass = INCLASS
+	defer func() {
+		r.currentClass = enclosingClass
+	}()
+	r.declare(statement.Name)
+	r.define(statement.Name)
+	if statement.Superclass != nil {
+		if statement.Superclass.Name.Lexeme == statement.Name.Lexeme {
+			panic(fmt.Sprintf("A class can't inherit from itself at line %d.", statement.Name.Line))
+		}
+		r.currentClass = SUBCLASS
+		r.resolveExpression(statement.Superclass)
+		r.beginScope()
+		defer r.endScope()
+		r.scopes[len(r.scopes)-1][SUPER] = true
+	}
+	r.beginScope()
+	defer r.endScope()
+	r.scopes[len(r.scopes)-1][THIS] = true
+	for _, field := range statement.Fields {
+		if field.Initializer != nil {
+			r.resolveExpression(field.Initializer)
+		}
+	}
+	for _, method := range statement.Methods {
+		functionType := METHOD
+		if method.Name.Lexeme == "init" {
+			functionType = INITIALIZER
+		}
+		r.resolveFunction(method, functionType)
+	}
+	return nil
+}
+
+func (r *Resolver) visitContinueStmt(statement *ContinueStmt) interface{} {
+	r.resolveLoopAction(statement.Token, statement.Label)
+	return nil
+}
+
+func (r *Resolver) visitEnumStmt(statement *EnumStmt) interface{} {
+	r.declare(statement.Name)
+	r.define(statement.Name)
+	return nil
+}
+
+func (r *Resolver) visitExportStmt(statement *ExportStmt) interface{} {
+	if len(r.scopes) > 0 {
+		panic(fmt.Sprintf("Can only use '%s' at the top level at line %d.", EXPORT, statement.Keyword.Line))
+	}
+	r.resolveStatement(statement.Declaration)
 	return nil
 }
 
@@ -145,29 +388,158 @@ func (r *Resolver) visitExpressionStmt(statement *ExpressionStmt) interface{} {
 func (r *Resolver) visitFunctionStmt(statement *FunctionStmt) interface{} {
 	r.declare(statement.Name)
 	r.define(statement.Name)
-	r.resolveFunction(statement)
+	r.resolveFunction(statement, FUNCTION)
 	return nil
 }
 
 func (r *Resolver) visitIfStmt(statement *IfStmt) interface{} {
 	r.resolveExpression(statement.Condition)
 	r.resolveStatement(statement.Then)
+	if statement.Else != nil {
+		r.resolveStatement(statement.Else)
+	}
+	return nil
+}
+
+func (r *Resolver) visitImportStmt(statement *ImportStmt) interface{} {
+	if r.currentFunction != NOFUNCTION {
+		panic(fmt.Sprintf("Can't use '%s' inside a function at line %d.", IMPORT, statement.Keyword.Line))
+	}
+	r.declare(statement.Alias)
+	r.define(statement.Alias)
 	return nil
 }
 
 func (r *Resolver) visitLoopStmt(statement *LoopStmt) interface{} {
+	label := ""
+	if statement.Label != nil {
+		label = statement.Label.Lexeme
+		for _, loop := range r.loops {
+			if loop == label {
+				panic(fmt.Sprintf("Loop label '%s' already in use at line %d.", label, statement.Label.Line))
+			}
+		}
+	}
+	r.loops = append(r.loops, label)
+	defer func() {
+		r.loops = r.loops[:len(r.loops)-1]
+	}()
+	if statement.Iterable != nil {
+		r.resolveExpression(statement.Iterable)
+	}
+	if statement.Initializer != nil {
+		r.beginScope()
+		defer r.endScope()
+		r.resolveStatement(statement.Initializer)
+	}
+	if statement.Condition != nil {
+		r.resolveExpression(statement.Condition)
+	}
+	if statement.Increment != nil {
+		r.resolveExpression(statement.Increment)
+	}
 	r.resolveStatement(statement.Body)
 	return nil
 }
 
+func (r *Resolver) visitMatchStmt(statement *MatchStmt) interface{} {
+	r.resolveExpression(statement.Subject)
+	for _, arm := range statement.Arms {
+		r.resolveArm(arm)
+	}
+	return nil
+}
+
+// resolveArm gives each arm a scope of its own for the names it binds.
+func (r *Resolver) resolveArm(arm *MatchArm) {
+	r.beginScope()
+	defer r.endScope()
+	r.resolvePattern(arm.Pattern)
+	if arm.Guard != nil {
+		r.resolveExpression(arm.Guard)
+	}
+	if arm.Body != nil {
+		r.resolveStatement(arm.Body)
+	} else {
+		r.resolveExpression(arm.Value)
+	}
+}
+
+func (r *Resolver) resolvePattern(pattern Pattern) {
+	switch pattern := pattern.(type) {
+	case *BindingPattern:
+		r.declare(pattern.Name)
+		r.define(pattern.Name)
+	case *ConstructorPattern:
+		r.resolveExpression(pattern.Constructor)
+		for _, field := range pattern.Fields {
+			r.resolvePattern(field)
+		}
+	case *ListPattern:
+		for _, element := range pattern.Elements {
+			r.resolvePattern(element)
+		}
+		if pattern.Rest != nil && pattern.Rest.Lexeme != "_" {
+			r.declare(*pattern.Rest)
+			r.define(*pattern.Rest)
+		}
+	case *MapPattern:
+		for index := range pattern.Keys {
+			r.resolveExpression(pattern.Keys[index])
+			r.resolvePattern(pattern.Values[index])
+		}
+	case *ValuePattern:
+		r.resolveExpression(pattern.Value)
+	}
+}
+
 func (r *Resolver) visitPrintStmt(statement *PrintStmt) interface{} {
 	r.resolveExpression(statement.Expression)
 	return nil
 }
 
+func (r *Resolver) visitRecordStmt(statement *RecordStmt) interface{} {
+	r.declare(statement.Name)
+	r.define(statement.Name)
+	return nil
+}
+
 func (r *Resolver) visitReturnStmt(statement *ReturnStmt) interface{} {
 	if statement.Value != nil {
+		if r.currentFunction == INITIALIZER {
+			panic(fmt.Sprintf("Can't return a value from an initializer at line %d.", statement.Keyword.Line))
+		}
 		r.resolveExpression(statement.Value)
+		// A tail call inside '☂' would escape its catch and finally blocks.
+		if r.currentFunction != NOFUNCTION && r.tries == 0 {
+			r.interpreter.resolveTail(statement)
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) visitThrowStmt(statement *ThrowStmt) interface{} {
+	r.resolveExpression(statement.Value)
+	return nil
+}
+
+func (r *Resolver) visitTryStmt(statement *TryStmt) interface{} {
+	r.tries++
+	defer func() {
+		r.tries--
+	}()
+	r.resolveStatement(statement.Body)
+	if statement.Catch != nil {
+		r.beginScope()
+		if statement.CatchName != nil {
+			r.declare(*statement.CatchName)
+			r.define(*statement.CatchName)
+		}
+		r.resolveStatements(statement.Catch.Statements)
+		r.endScope()
+	}
+	if statement.Finally != nil {
+		r.resolveStatement(statement.Finally)
 	}
 	return nil
 }
@@ -178,5 +550,19 @@ func (r *Resolver) visitVarStmt(statement *VarStmt) interface{} {
 		r.resolveExpression(statement.Initializer)
 	}
 	r.define(statement.Name)
+	if statement.Constant && len(r.constants) > 0 {
+		r.constants[len(r.constants)-1][statement.Name.Lexeme] = true
+	}
+	return nil
+}
+
+func (r *Resolver) visitYieldStmt(statement *YieldStmt) interface{} {
+	if r.currentFunction == NOFUNCTION {
+		panic(fmt.Sprintf("Can't use '%s' outside of a function at line %d.", YIELD, statement.Keyword.Line))
+	} else if r.currentFunction == INITIALIZER {
+		panic(fmt.Sprintf("Can't use '%s' in an initializer at line %d.", YIELD, statement.Keyword.Line))
+	}
+	r.resolveExpression(statement.Value)
+	r.interpreter.resolveGenerator(r.declaration)
 	return nil
 }
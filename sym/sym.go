@@ -3,15 +3,24 @@ package sym
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 type Runtime struct {
+	// SearchPath starts out as the directories in SYMPATH.
+	SearchPath  []string
 	interpreter *Interpreter
 }
 
 func NewRuntime() Runtime {
 	interpreter := NewInterpreter()
+	var searchPath []string
+	if os.Getenv("SYMPATH") != "" {
+		searchPath = filepath.SplitList(os.Getenv("SYMPATH"))
+	}
 	return Runtime{
+		SearchPath:  searchPath,
 		interpreter: interpreter,
 	}
 }
@@ -21,6 +30,15 @@ func (r *Runtime) ExecFile(path string) {
 	if err != nil {
 		panic(err)
 	}
+	file, err := filepath.Abs(path)
+	if err != nil {
+		panic(err)
+	}
+	module := r.interpreter.module
+	module.Name = strings.TrimSuffix(filepath.Base(file), MODULEEXTENSION)
+	module.Path = file
+	r.interpreter.importing = append(r.interpreter.importing, file)
+	r.interpreter.searchPath = r.SearchPath
 	r.exec(string(input))
 }
 
@@ -33,6 +51,7 @@ func (r *Runtime) exec(source string) {
 	//	r.debugStatements(statements)
 	resolver := NewResolver(r.interpreter)
 	resolver.resolveStatements(statements)
+	defer r.interpreter.reportFailedTasks()
 	result := r.interpreter.interpret(statements)
 	fmt.Printf("Result: %v\n", result)
 }
@@ -5,6 +5,8 @@ type Token struct {
 	Lexeme    string
 	Literal   interface{}
 	Line      int
+	Leading   []Trivia
+	Trailing  []Trivia
 }
 
 func NewToken(tokenType string, lexeme string, literal interface{}, line int) Token {
@@ -16,20 +18,72 @@ func NewToken(tokenType string, lexeme string, literal interface{}, line int) To
 	}
 }
 
+// Trivia is source text that the parser ignores, such as a comment.
+type Trivia struct {
+	TriviaType string
+	Text       string
+	Line       int
+}
+
+func NewTrivia(triviaType string, text string, line int) Trivia {
+	return Trivia{
+		TriviaType: triviaType,
+		Text:       text,
+		Line:       line,
+	}
+}
+
+type StringPart struct {
+	Text         string
+	IsExpression bool
+	Line         int
+}
+
+func NewStringPart(text string, isExpression bool, line int) StringPart {
+	return StringPart{
+		Text:         text,
+		IsExpression: isExpression,
+		Line:         line,
+	}
+}
+
+const (
+	LINECOMMENT  = "LineComment"
+	BLOCKCOMMENT = "BlockComment"
+	SHEBANG      = "Shebang"
+)
+
 const (
 	LEFTPARENTHESIS  = "("
 	RIGHTPARENTHESIS = ")"
 	LEFTBRACE        = "{"
 	RIGHTBRACE       = "}"
+	LEFTBRACKET      = "["
+	RIGHTBRACKET     = "]"
 
+	ARROW     = "→"
 	COMMA     = ","
 	DOT       = "."
+	ELLIPSIS  = "…"
 	SEMICOLON = ";"
 
-	MINUS    = "-"
-	PLUS     = "+"
-	DIVIDE   = "÷"
-	MULTIPLY = "×"
+	MINUS       = "-"
+	PLUS        = "+"
+	DIVIDE      = "÷"
+	MULTIPLY    = "×"
+	MODULO      = "%"
+	POWER       = "^"
+	FLOORDIVIDE = "⌊÷"
+
+	MINUSASSIGN       = "-←"
+	PLUSASSIGN        = "+←"
+	DIVIDEASSIGN      = "÷←"
+	MULTIPLYASSIGN    = "×←"
+	MODULOASSIGN      = "%←"
+	POWERASSIGN       = "^←"
+	FLOORDIVIDEASSIGN = "⌊÷←"
+	DECREMENT         = "--"
+	INCREMENT         = "++"
 
 	BANG         = "!"
 	EQUAL        = "="
@@ -38,24 +92,44 @@ const (
 	GREATEREQUAL = "≥"
 	LESS         = "<"
 	LESSEQUAL    = "≤"
+	IN           = "∈"
 
 	IDENTIFIER = "Identifier"
+	LABEL      = "Label"
 	STRING     = "String"
+	TEMPLATE   = "Template"
 	NUMBER     = "Number"
 
-	AND    = "&"
-	ASSIGN = "←"
-	BREAK  = "Ɵ"
-	FALSE  = "○"
-	FUNC   = "ƒ"
-	IF     = "¿"
-	LOOP   = "∞"
-	NIL    = "ø"
-	OR     = "|"
-	PRINT  = "✉"
-	RETURN = "↵"
-	TRUE   = "●"
-	VAR    = "•"
+	AND      = "&"
+	ASSIGN   = "←"
+	BREAK    = "Ɵ"
+	CATCH    = "⚐"
+	CLASS    = "©"
+	CONST    = "≡"
+	CONTINUE = "↻"
+	ELSE     = "¡"
+	ENUM     = "∑"
+	EXPORT   = "⇑"
+	FALSE    = "○"
+	FINALLY  = "∎"
+	FUNC     = "ƒ"
+	IF       = "¿"
+	IMPORT   = "⇓"
+	LOOP     = "∞"
+	MATCH    = "⋔"
+	NIL      = "ø"
+	OR       = "|"
+	PRINT    = "✉"
+	RECORD   = "®"
+	SPAWN    = "⇶"
+	RETURN   = "↵"
+	SUPER    = "↑"
+	THIS     = "þ"
+	THROW    = "↯"
+	TRUE     = "●"
+	TRY      = "☂"
+	VAR      = "•"
+	YIELD    = "↳"
 
 	EOF = "EOF"
 )